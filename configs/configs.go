@@ -17,6 +17,41 @@ type Config struct {
 	SERVERPORT string `mapstructure:"SERVERPORT"`
 	Sslmode    string `mapstructure:"SSL"`
 	REDISHOST  string `mapstructure:"REDISHOST"`
+
+	AccessTokenMinutes     int `mapstructure:"ACCESS_TOKEN_MINUTES"`
+	RefreshTokenDays       int `mapstructure:"REFRESH_TOKEN_DAYS"`
+	TrashRetentionDays     int `mapstructure:"TRASH_RETENTION_DAYS"`
+	IdempotencyWindowHours int `mapstructure:"IDEMPOTENCY_WINDOW_HOURS"`
+
+	StorageDriver        string `mapstructure:"STORAGE_DRIVER"`
+	StorageDir           string `mapstructure:"STORAGE_DIR"`
+	S3Endpoint           string `mapstructure:"S3_ENDPOINT"`
+	S3Region             string `mapstructure:"S3_REGION"`
+	S3Bucket             string `mapstructure:"S3_BUCKET"`
+	S3AccessKey          string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey          string `mapstructure:"S3_SECRET_KEY"`
+	S3PathStyle          bool   `mapstructure:"S3_PATH_STYLE"`
+	AttachmentMaxBytes   int64  `mapstructure:"ATTACHMENT_MAX_BYTES"`
+	AttachmentQuotaBytes int64  `mapstructure:"ATTACHMENT_QUOTA_BYTES"`
+
+	ChecklistAutoComplete bool `mapstructure:"CHECKLIST_AUTO_COMPLETE"`
+
+	AppURL               string `mapstructure:"APP_URL"`
+	MailDriver           string `mapstructure:"MAIL_DRIVER"`
+	MailFrom             string `mapstructure:"MAIL_FROM"`
+	SMTPHost             string `mapstructure:"SMTP_HOST"`
+	SMTPPort             string `mapstructure:"SMTP_PORT"`
+	SMTPUsername         string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword         string `mapstructure:"SMTP_PASSWORD"`
+	PasswordResetMinutes int    `mapstructure:"PASSWORD_RESET_MINUTES"`
+
+	EmailVerifyHours         int  `mapstructure:"EMAIL_VERIFY_HOURS"`
+	EmailVerifyResendSeconds int  `mapstructure:"EMAIL_VERIFY_RESEND_SECONDS"`
+	RequireVerifiedLogin     bool `mapstructure:"REQUIRE_VERIFIED_LOGIN"`
+	RequireVerifiedTasks     bool `mapstructure:"REQUIRE_VERIFIED_TASKS"`
+
+	TOTPIssuer                string `mapstructure:"TOTP_ISSUER"`
+	TwoFactorChallengeMinutes int    `mapstructure:"TWO_FACTOR_CHALLENGE_MINUTES"`
 }
 
 func LoadConfig() *Config {
@@ -35,11 +70,44 @@ func LoadConfig() *Config {
 	keys := []string{
 		"JWTSECRET", "HOST", "DBUSER", "PASSWORD", "DBNAME",
 		"PORT", "SERVERPORT", "SSL", "REDISHOST",
+		"ACCESS_TOKEN_MINUTES", "REFRESH_TOKEN_DAYS",
+		"TRASH_RETENTION_DAYS", "IDEMPOTENCY_WINDOW_HOURS",
+		"STORAGE_DRIVER", "STORAGE_DIR", "S3_ENDPOINT", "S3_REGION", "S3_BUCKET",
+		"S3_ACCESS_KEY", "S3_SECRET_KEY", "S3_PATH_STYLE",
+		"ATTACHMENT_MAX_BYTES", "ATTACHMENT_QUOTA_BYTES", "CHECKLIST_AUTO_COMPLETE",
+		"APP_URL", "MAIL_DRIVER", "MAIL_FROM", "SMTP_HOST", "SMTP_PORT",
+		"SMTP_USERNAME", "SMTP_PASSWORD", "PASSWORD_RESET_MINUTES",
+		"EMAIL_VERIFY_HOURS", "EMAIL_VERIFY_RESEND_SECONDS",
+		"REQUIRE_VERIFIED_LOGIN", "REQUIRE_VERIFIED_TASKS",
+		"TOTP_ISSUER", "TWO_FACTOR_CHALLENGE_MINUTES",
 	}
 	for _, key := range keys {
 		_ = viper.BindEnv(key)
 	}
 
+	// Defaults for optional settings
+	viper.SetDefault("ACCESS_TOKEN_MINUTES", 15)
+	viper.SetDefault("REFRESH_TOKEN_DAYS", 30)
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	viper.SetDefault("IDEMPOTENCY_WINDOW_HOURS", 24)
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_DIR", "./uploads")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_PATH_STYLE", true)
+	viper.SetDefault("ATTACHMENT_MAX_BYTES", 10<<20)
+	viper.SetDefault("ATTACHMENT_QUOTA_BYTES", 100<<20)
+	viper.SetDefault("CHECKLIST_AUTO_COMPLETE", false)
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FROM", "no-reply@todo-app.local")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("PASSWORD_RESET_MINUTES", 30)
+	viper.SetDefault("EMAIL_VERIFY_HOURS", 24)
+	viper.SetDefault("EMAIL_VERIFY_RESEND_SECONDS", 60)
+	viper.SetDefault("REQUIRE_VERIFIED_LOGIN", false)
+	viper.SetDefault("REQUIRE_VERIFIED_TASKS", false)
+	viper.SetDefault("TOTP_ISSUER", "todo-app")
+	viper.SetDefault("TWO_FACTOR_CHALLENGE_MINUTES", 5)
+
 	if err := viper.Unmarshal(&config); err != nil {
 		log.Fatalf("Unable to decode into config struct: %v", err)
 	}
@@ -0,0 +1,56 @@
+package boot
+
+import (
+	"context"
+	"time"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"go.uber.org/zap"
+)
+
+// trashPurgeInterval is how often expired tasks are removed from the trash
+const trashPurgeInterval = time.Hour
+
+// attachmentSweepInterval is how often the content of attachments of purged tasks is removed
+const attachmentSweepInterval = time.Hour
+
+// startTrashPurger permanently deletes tasks that stayed in the trash longer than the retention
+// period, it runs until the context is cancelled.
+func startTrashPurger(ctx context.Context, taskSvc interfaces.TaskServiceInterface, retentionDays int, logger *zap.Logger) {
+	retention := time.Duration(retentionDays) * 24 * time.Hour
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := taskSvc.PurgeExpiredTrashSvc(ctx, retention)
+		if err == nil && purged > 0 {
+			logger.Info("Purged expired tasks from trash", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startAttachmentSweeper removes the stored content of attachments whose task was purged, it
+// runs until the context is cancelled.
+func startAttachmentSweeper(ctx context.Context, attachmentSvc interfaces.AttachmentServiceInterface, logger *zap.Logger) {
+	ticker := time.NewTicker(attachmentSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		removed, err := attachmentSvc.SweepOrphanedAttachmentsSvc(ctx)
+		if err == nil && removed > 0 {
+			logger.Info("Removed attachments of purged tasks", zap.Int64("count", removed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
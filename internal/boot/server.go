@@ -8,8 +8,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shivarajshanthaiah/todo-app/configs"
+	"github.com/shivarajshanthaiah/todo-app/internal/clients/mailer"
 	"github.com/shivarajshanthaiah/todo-app/internal/clients/psql"
 	"github.com/shivarajshanthaiah/todo-app/internal/clients/redis"
+	"github.com/shivarajshanthaiah/todo-app/internal/clients/storage"
 	"github.com/shivarajshanthaiah/todo-app/internal/handler"
 	"github.com/shivarajshanthaiah/todo-app/internal/repo"
 	"github.com/shivarajshanthaiah/todo-app/internal/routes"
@@ -31,15 +33,50 @@ func (s *Server) StartServer(port string) error {
 	if s.DB != nil {
 		fmt.Println("not nil")
 	}
+	projectRepo := repo.NewProjectRepository(s.DB)
+	projectSvc := service.NewProjectService(projectRepo, s.Logger)
+	projectHandler := handler.NewProjectHandler(projectSvc)
+
+	tagRepo := repo.NewTagRepository(s.DB)
+	tagSvc := service.NewTagService(tagRepo, s.Logger)
+	tagHandler := handler.NewTagHandler(tagSvc)
+
+	statusRepo := repo.NewStatusRepository(s.DB)
+	statusSvc := service.NewStatusService(statusRepo, s.Logger)
+	statusHandler := handler.NewStatusHandler(statusSvc)
+
+	dependencyRepo := repo.NewDependencyRepository(s.DB)
+	revisionRepo := repo.NewRevisionRepository(s.DB)
+	commentRepo := repo.NewCommentRepository(s.DB)
+	checklistRepo := repo.NewChecklistRepository(s.DB)
 	taskRepo := repo.NewTaskRepository(s.DB)
-	taskSvc := service.NewTaskService(taskRepo, s.Logger)
+	taskSvc := service.NewTaskService(taskRepo, projectRepo, tagRepo, dependencyRepo, statusRepo, revisionRepo, commentRepo, checklistRepo, s.Cnfg.ChecklistAutoComplete, s.Logger)
 	taskHandler := handler.NewTaskHandler(taskSvc)
 
+	commentSvc := service.NewCommentService(commentRepo, taskRepo, s.Logger)
+	commentHandler := handler.NewCommentHandler(commentSvc)
+	go startTrashPurger(context.Background(), taskSvc, s.Cnfg.TrashRetentionDays, s.Logger)
+
+	store, err := storage.NewStorage(s.Cnfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up attachment storage: %v", err)
+	}
+	attachmentRepo := repo.NewAttachmentRepository(s.DB)
+	attachmentSvc := service.NewAttachmentService(attachmentRepo, taskRepo, store, s.Cnfg, s.Logger)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentSvc)
+	go startAttachmentSweeper(context.Background(), attachmentSvc, s.Logger)
+
+	mail, err := mailer.NewMailer(s.Cnfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up mailer: %v", err)
+	}
 	userRepo := repo.NewUserRepository(s.DB)
-	userSvc := service.NewUserService(userRepo, s.Cnfg, s.Redis, s.Logger)
+	tokenRepo := repo.NewTokenRepository(s.DB)
+	sessionRepo := repo.NewSessionRepository(s.DB)
+	userSvc := service.NewUserService(userRepo, projectRepo, tokenRepo, sessionRepo, mail, s.Cnfg, s.Redis, s.Logger)
 	userHandler := handler.NewUserHandler(userSvc)
 
-	routes.RegisterRoutes(s.R, taskHandler, userHandler, s.Cnfg)
+	routes.RegisterRoutes(s.R, taskHandler, userHandler, projectHandler, tagHandler, statusHandler, commentHandler, attachmentHandler, s.Redis, s.Cnfg)
 	return s.R.Run(":" + port)
 }
 
@@ -113,6 +150,252 @@ CREATE TABLE IF NOT EXISTS tasks (
   status INT NOT NULL DEFAULT 1,
   due_at TIMESTAMP
 );
+
+-- databases created from migrations/scripts.sql before subtasks have no key on tasks.id
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'tasks'::regclass AND contype = 'p') THEN
+    ALTER TABLE tasks ADD PRIMARY KEY (id);
+  END IF;
+END $$;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES tasks(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id);
+
+CREATE TABLE IF NOT EXISTS projects (
+  id SERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  name VARCHAR(119) NOT NULL,
+  is_default BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_user_name ON projects (user_id, lower(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_user_default ON projects (user_id) WHERE is_default;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id INT REFERENCES projects(id);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks (project_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+  id SERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  name VARCHAR(63) NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags (tag_id);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence TEXT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS occurrence INT NOT NULL DEFAULT 1;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+  setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+UPDATE tasks SET due_at = NULL WHERE due_at = '0001-01-01 00:00:00';
+CREATE INDEX IF NOT EXISTS idx_tasks_user_due_at ON tasks (user_id, due_at);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  blocked_by_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  created_at TIMESTAMP DEFAULT now(),
+  PRIMARY KEY (task_id, blocked_by_id),
+  CHECK (task_id <> blocked_by_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by_id ON task_dependencies (blocked_by_id);
+
+CREATE TABLE IF NOT EXISTS statuses (
+  id SERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  name VARCHAR(63) NOT NULL,
+  position INT NOT NULL DEFAULT 0,
+  color VARCHAR(7) NOT NULL DEFAULT '#9e9e9e',
+  is_terminal BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statuses_user_name ON statuses (user_id, upper(name));
+
+INSERT INTO statuses (user_id, name, position, color, is_terminal)
+SELECT u.id, d.name, d.position, d.color, d.is_terminal
+FROM (SELECT id FROM users UNION SELECT user_id FROM tasks) u
+CROSS JOIN (VALUES ('PENDING', 1, '#f0ad4e', false), ('COMPLETED', 2, '#5cb85c', true)) AS d (name, position, color, is_terminal)
+WHERE NOT EXISTS (SELECT 1 FROM statuses s WHERE s.user_id = u.id)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status_id INT REFERENCES statuses(id);
+UPDATE tasks t SET status_id = s.id FROM statuses s
+WHERE t.status_id IS NULL AND s.user_id = t.user_id
+  AND s.name = CASE t.status WHEN 2 THEN 'COMPLETED' ELSE 'PENDING' END;
+ALTER TABLE tasks ALTER COLUMN status_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_status_id ON tasks (status_id);
+
+CREATE TABLE IF NOT EXISTS status_transitions (
+  from_status_id INT NOT NULL REFERENCES statuses(id) ON DELETE CASCADE,
+  to_status_id INT NOT NULL REFERENCES statuses(id) ON DELETE CASCADE,
+  PRIMARY KEY (from_status_id, to_status_id),
+  CHECK (from_status_id <> to_status_id)
+);
+
+CREATE TABLE IF NOT EXISTS task_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  from_status_id INT REFERENCES statuses(id) ON DELETE SET NULL,
+  to_status_id INT REFERENCES statuses(id) ON DELETE SET NULL,
+  changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_task_status_history_task_id ON task_status_history (task_id, changed_at);
+INSERT INTO task_status_history (task_id, to_status_id, changed_at)
+SELECT t.id, t.status_id, COALESCE(t.created_at, now()) FROM tasks t
+WHERE NOT EXISTS (SELECT 1 FROM task_status_history h WHERE h.task_id = t.id);
+
+CREATE TABLE IF NOT EXISTS task_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  revision INT NOT NULL,
+  action VARCHAR(15) NOT NULL,
+  actor_id VARCHAR(63) NOT NULL,
+  reverted_to INT,
+  changes JSONB NOT NULL DEFAULT '{}',
+  snapshot JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE (task_id, revision)
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+  id SERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  user_id VARCHAR(63) NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_comments_task_id ON comments (task_id, created_at);
+
+CREATE TABLE IF NOT EXISTS attachments (
+  id SERIAL PRIMARY KEY,
+  task_id INT REFERENCES tasks(id) ON DELETE SET NULL,
+  user_id VARCHAR(63) NOT NULL,
+  filename VARCHAR(255) NOT NULL,
+  content_type VARCHAR(127) NOT NULL,
+  size BIGINT NOT NULL,
+  storage_key VARCHAR(255) NOT NULL UNIQUE,
+  created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments (task_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_attachments_user_id ON attachments (user_id);
+
+CREATE TABLE IF NOT EXISTS checklist_items (
+  id SERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  text VARCHAR(500) NOT NULL,
+  done BOOLEAN NOT NULL DEFAULT false,
+  position INT NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_checklist_items_task_id ON checklist_items (task_id, position);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  family_id VARCHAR(63) NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  access_jti VARCHAR(63) NOT NULL,
+  access_expires_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  used_at TIMESTAMP,
+  revoked_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_access_jti ON refresh_tokens (access_jti);
+
+CREATE TABLE IF NOT EXISTS sessions (
+  id VARCHAR(63) PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  user_agent VARCHAR(255) NOT NULL DEFAULT '',
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMP NOT NULL DEFAULT now(),
+  expires_at TIMESTAMP NOT NULL,
+  revoked_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id, last_seen_at);
+
+-- Logins made before sessions were recorded
+INSERT INTO sessions (id, user_id, created_at, last_seen_at, expires_at)
+SELECT family_id, user_id, MIN(created_at), MAX(created_at), MAX(expires_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
+
+-- Every login attempt, user_id is empty when the email matched no user
+CREATE TABLE IF NOT EXISTS login_attempts (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63),
+  email VARCHAR(63) NOT NULL,
+  success BOOLEAN NOT NULL,
+  reason VARCHAR(63) NOT NULL DEFAULT '',
+  user_agent VARCHAR(255) NOT NULL DEFAULT '',
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_id ON login_attempts (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS password_resets (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_password_resets_user_id ON password_resets (user_id);
+
+-- accounts created before verification existed count as verified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP DEFAULT now();
+ALTER TABLE users ALTER COLUMN email_verified_at DROP DEFAULT;
+
+CREATE TABLE IF NOT EXISTS email_verifications (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user_id ON email_verifications (user_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
 `
 	_, err := db.Exec(context.Background(), schema)
 	if err != nil {
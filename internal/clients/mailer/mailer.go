@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shivarajshanthaiah/todo-app/configs"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails to users
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewMailer returns the mailer selected by MAIL_DRIVER, "log" or "smtp"
+func NewMailer(cfg *configs.Config) (Mailer, error) {
+	switch cfg.MailDriver {
+	case "", "log":
+		return NewLogMailer(), nil
+	case "smtp":
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+	return nil, fmt.Errorf("unknown mail driver %q", cfg.MailDriver)
+}
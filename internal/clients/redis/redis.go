@@ -50,3 +50,34 @@ func (r *RedisService) GetFromRedis(key string) (string, error) {
 	}
 	return jsonData, nil
 }
+
+// SetIfAbsentInRedis sets the key only when it does not exist yet, it reports whether the key was set.
+func (r *RedisService) SetIfAbsentInRedis(key string, value []byte, expTime time.Duration) (bool, error) {
+	return r.Client.SetNX(context.Background(), key, value, expTime).Result()
+}
+
+// DeleteFromRedis removes a key from redis.
+func (r *RedisService) DeleteFromRedis(key string) error {
+	return r.Client.Del(context.Background(), key).Err()
+}
+
+// ExistsInRedis reports whether a key is set in redis.
+func (r *RedisService) ExistsInRedis(key string) (bool, error) {
+	n, err := r.Client.Exists(context.Background(), key).Result()
+	return n > 0, err
+}
+
+// incrWithTTL increments a counter and sets its expiry when the increment created it, in one step
+var incrWithTTL = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// IncrInRedis atomically increments a counter and returns its new value. A new counter expires
+// after expTime, later increments keep that expiry.
+func (r *RedisService) IncrInRedis(key string, expTime time.Duration) (int64, error) {
+	return incrWithTTL.Run(context.Background(), r.Client, []string{key}, expTime.Milliseconds()).Int64()
+}
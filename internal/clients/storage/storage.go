@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/shivarajshanthaiah/todo-app/configs"
+)
+
+// ErrObjectNotFound is returned when reading an object that is not in the store
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage keeps the bytes of uploaded files under opaque keys
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage returns the store selected by STORAGE_DRIVER, "local" or "s3"
+func NewStorage(cfg *configs.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalStorage(cfg.StorageDir)
+	case "s3":
+		return NewS3Storage(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3PathStyle)
+	}
+	return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+}
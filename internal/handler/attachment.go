@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+)
+
+type AttachmentHandler struct {
+	service interfaces.AttachmentServiceInterface
+}
+
+func NewAttachmentHandler(service interfaces.AttachmentServiceInterface) *AttachmentHandler {
+	return &AttachmentHandler{service: service}
+}
+
+// UploadAttachmentHandler stores the multipart form file "file" on a todo
+func (h *AttachmentHandler) UploadAttachmentHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error reading uploaded file",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Status":  http.StatusInternalServerError,
+			"Message": "Error reading uploaded file",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.UploadAttachmentSvc(ctx, taskID, userID, header.Filename, header.Size, file)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error uploading attachment",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"Status":  http.StatusCreated,
+		"Message": "Attachment uploaded successfully",
+		"Data":    attachment,
+	})
+}
+
+func (h *AttachmentHandler) ListAttachmentsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	attachments, err := h.service.ListAttachmentsSvc(ctx, taskID, userID)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching attachments",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Attachments fetched successfully",
+		"Data":    attachments,
+	})
+}
+
+// DownloadAttachmentHandler streams the content of an attachment. It is always sent as a download
+// with the sniffed type, so the browser never renders it in the page.
+func (h *AttachmentHandler) DownloadAttachmentHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.service.DownloadAttachmentSvc(ctx, taskID, attachmentID, userID)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error downloading attachment",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *AttachmentHandler) DeleteAttachmentHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAttachmentSvc(ctx, taskID, attachmentID, userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error deleting attachment",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Attachment deleted successfully",
+	})
+}
+
+// attachmentParams reads the task and attachment ids from the path, it writes the error response when invalid
+func attachmentParams(c *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return 0, 0, false
+	}
+
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid attachment ID",
+			"Error":   err.Error(),
+		})
+		return 0, 0, false
+	}
+	return taskID, attachmentID, true
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+)
+
+// AddChecklistItemHandler appends an item to the checklist of a todo
+func (h *TaskHandler) AddChecklistItemHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var item models.ChecklistItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	checklist, err := h.service.AddChecklistItemSvc(ctx, taskID, userID, item.Text)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error adding checklist item",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"Status":  http.StatusCreated,
+		"Message": "Checklist item added successfully",
+		"Data":    checklist,
+	})
+}
+
+// UpdateChecklistItemHandler edits the text of an item or checks and unchecks it
+func (h *TaskHandler) UpdateChecklistItemHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, itemID, ok := checklistParams(c)
+	if !ok {
+		return
+	}
+
+	var patch models.ChecklistItemPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	checklist, err := h.service.UpdateChecklistItemSvc(ctx, taskID, itemID, userID, &patch)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error updating checklist item",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Checklist item updated successfully",
+		"Data":    checklist,
+	})
+}
+
+// ReorderChecklistHandler puts the items of a checklist in the order of the listed ids
+func (h *TaskHandler) ReorderChecklistHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var order models.ChecklistOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	checklist, err := h.service.ReorderChecklistSvc(ctx, taskID, userID, order.ItemIDs)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error reordering checklist",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Checklist reordered successfully",
+		"Data":    checklist,
+	})
+}
+
+func (h *TaskHandler) DeleteChecklistItemHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, itemID, ok := checklistParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteChecklistItemSvc(ctx, taskID, itemID, userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error deleting checklist item",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Checklist item deleted successfully",
+	})
+}
+
+// checklistParams reads the task and item ids from the path, it writes the error response when invalid
+func checklistParams(c *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return 0, 0, false
+	}
+
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid checklist item ID",
+			"Error":   err.Error(),
+		})
+		return 0, 0, false
+	}
+	return taskID, itemID, true
+}
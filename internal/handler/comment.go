@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+type CommentHandler struct {
+	service interfaces.CommentServiceInterface
+}
+
+func NewCommentHandler(service interfaces.CommentServiceInterface) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+func (h *CommentHandler) AddCommentHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var comment models.Comment
+	if err := c.ShouldBindJSON(&comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	comment.TodoID = int64(taskID)
+	comment.UserID = userID
+
+	if err := h.service.AddCommentSvc(ctx, &comment); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error adding comment",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"Status":  http.StatusCreated,
+		"Message": "Comment added successfully",
+		"Data":    comment,
+	})
+}
+
+// ListCommentsHandler lists the comments of a todo oldest first, paged with ?limit and ?offset
+func (h *CommentHandler) ListCommentsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var page models.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid query parameters",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	if page.Limit <= 0 {
+		page.Limit = 10
+	}
+	if page.Limit > globals.MaxPageLimit {
+		page.Limit = globals.MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	comments, err := h.service.ListCommentsSvc(ctx, taskID, userID, page.Limit, page.Offset)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching comments",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Comments fetched successfully",
+		"Data":    comments,
+	})
+}
+
+// UpdateCommentHandler replaces the text of a comment written by the user
+func (h *CommentHandler) UpdateCommentHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.Comment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	comment, err := h.service.UpdateCommentSvc(ctx, taskID, commentID, userID, req.Body)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error updating comment",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Comment updated successfully",
+		"Data":    comment,
+	})
+}
+
+func (h *CommentHandler) DeleteCommentHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCommentSvc(ctx, taskID, commentID, userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error deleting comment",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Comment deleted successfully",
+	})
+}
+
+// commentParams reads the task and comment ids from the path, it writes the error response when invalid
+func commentParams(c *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return 0, 0, false
+	}
+
+	commentID, err := strconv.Atoi(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid comment ID",
+			"Error":   err.Error(),
+		})
+		return 0, 0, false
+	}
+	return taskID, commentID, true
+}
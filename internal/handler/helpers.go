@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// userIDFromContext reads the user id set by the auth middleware, it writes the error response when missing
+func userIDFromContext(c *gin.Context) (string, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error while user id from context",
+			"Error":   ""})
+		return "", false
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error while converting user id to string",
+			"Error":   ""})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// clientInfo describes the device a request comes from
+func clientInfo(c *gin.Context) *models.Client {
+	return &models.Client{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// errorStatus maps an error returned by a service to the HTTP status sent to the client
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, globals.ErrTaskNotFound), errors.Is(err, globals.ErrDependencyNotFound),
+		errors.Is(err, globals.ErrStatusNotFound), errors.Is(err, globals.ErrRevisionNotFound),
+		errors.Is(err, globals.ErrCommentNotFound), errors.Is(err, globals.ErrAttachmentNotFound),
+		errors.Is(err, globals.ErrChecklistItemNotFound), errors.Is(err, globals.ErrSessionNotFound),
+		errors.Is(err, globals.ErrProjectNotFound), errors.Is(err, globals.ErrTagNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, globals.ErrTaskForbidden), errors.Is(err, globals.ErrCommentForbidden),
+		errors.Is(err, globals.ErrEmailNotVerified):
+		return http.StatusForbidden
+	case errors.Is(err, globals.ErrValidation), errors.Is(err, globals.ErrInvalidResetToken),
+		errors.Is(err, globals.ErrInvalidVerificationToken):
+		return http.StatusBadRequest
+	case errors.Is(err, globals.ErrInvalidRefreshToken), errors.Is(err, globals.ErrRefreshTokenReused),
+		errors.Is(err, globals.ErrIncorrectPassword), errors.Is(err, globals.ErrInvalidTwoFactorCode),
+		errors.Is(err, globals.ErrInvalidLoginChallenge):
+		return http.StatusUnauthorized
+	case errors.Is(err, globals.ErrTaskBlocked), errors.Is(err, globals.ErrDependencyCycle),
+		errors.Is(err, globals.ErrTransitionNotAllowed), errors.Is(err, globals.ErrTwoFactorEnabled),
+		errors.Is(err, globals.ErrTwoFactorNotEnabled):
+		return http.StatusConflict
+	case errors.Is(err, globals.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, globals.ErrAttachmentTooLarge), errors.Is(err, globals.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, globals.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, globals.ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
+// errorBody builds the response of a failed todo update, an illegal status transition also
+// lists the statuses the todo may move to
+func errorBody(status int, message string, err error) gin.H {
+	body := gin.H{
+		"Status":  status,
+		"Message": message,
+		"Error":   err.Error(),
+	}
+	var transition *globals.TransitionError
+	if errors.As(err, &transition) {
+		body["Allowed"] = transition.Allowed
+	}
+	return body
+}
+
+// etag builds the entity tag of a todo from its id and version
+func etag(id, version int64) string {
+	return fmt.Sprintf(`"%d-%d"`, id, version)
+}
+
+// etagVersion reads the version out of an entity tag issued for the given todo, weak tags are accepted
+func etagVersion(tag string, id int64) (int64, bool) {
+	tag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "W/"), `"`)
+	idPart, versionPart, ok := strings.Cut(tag, "-")
+	if !ok || idPart != strconv.FormatInt(id, 10) {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(versionPart, 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// ifMatchVersion returns the version required by the If-Match header, 0 when the header is absent
+// or "*". It writes the 412 response when the header does not name a version of this todo.
+func ifMatchVersion(c *gin.Context, id int64) (int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	version, ok := etagVersion(header, id)
+	if !ok {
+		c.JSON(http.StatusPreconditionFailed, gin.H{
+			"Status":  http.StatusPreconditionFailed,
+			"Message": "If-Match does not match the current version of the todo",
+			"Error":   globals.ErrPreconditionFailed.Error(),
+		})
+		return 0, false
+	}
+	return version, true
+}
+
+// noneMatch reports whether the If-None-Match header lists the current entity tag
+func noneMatch(c *gin.Context, id, version int64) bool {
+	header := strings.TrimSpace(c.GetHeader("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if v, ok := etagVersion(tag, id); ok && v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+)
+
+// ForgotPasswordHandler mails a password reset token. It answers the same whether or not
+// an account exists for the email.
+func (h *UserHandler) ForgotPasswordHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	var req models.ForgotPassword
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	if err := h.service.ForgotPasswordSvc(ctx, req.Email); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error requesting password reset",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"Status":  http.StatusAccepted,
+		"Message": "if an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// ResetPasswordHandler sets a new password with a reset token and logs the user out everywhere
+func (h *UserHandler) ResetPasswordHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	var req models.ResetPassword
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	if err := h.service.ResetPasswordSvc(ctx, req.Token, req.Password); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error resetting password",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "password reset successfully",
+	})
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+)
+
+type ProjectHandler struct {
+	service interfaces.ProjectServiceInterface
+}
+
+func NewProjectHandler(service interfaces.ProjectServiceInterface) *ProjectHandler {
+	return &ProjectHandler{service: service}
+}
+
+func (h *ProjectHandler) CreateProjectHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	var project models.Project
+	if err := c.ShouldBindJSON(&project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	project.UserID = userID
+
+	if err := h.service.CreateProjectSvc(ctx, &project); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error creating project",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"Status":  http.StatusCreated,
+		"Message": "Project created successfully",
+		"Data":    project,
+	})
+}
+
+func (h *ProjectHandler) ListProjectsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	projects, err := h.service.ListProjectsSvc(ctx, userID)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching projects",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Projects fetched successfully",
+		"Data":    projects,
+	})
+}
+
+func (h *ProjectHandler) UpdateProjectHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid project ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var project models.Project
+	if err := c.ShouldBindJSON(&project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	project.ID = int64(id)
+	project.UserID = userID
+
+	if err := h.service.UpdateProjectSvc(ctx, &project); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error updating project",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Project updated successfully",
+		"Data":    project,
+	})
+}
+
+// DeleteProjectHandler deletes a project, its tasks are moved to the Inbox unless
+// ?move_to=<project id> or ?delete_tasks=true is given.
+func (h *ProjectHandler) DeleteProjectHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid project ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var opts models.DeleteProject
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid query parameters",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.DeleteProjectSvc(ctx, id, userID, &opts); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error deleting project",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Project deleted successfully",
+	})
+}
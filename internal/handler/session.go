@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// ListSessionsHandler lists the devices the user is logged in on
+func (h *UserHandler) ListSessionsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.service.ListSessionsSvc(ctx, userID, c.GetString("jti"))
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error fetching sessions",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "sessions fetched successfully",
+		"Data":    sessions,
+	})
+}
+
+// RevokeSessionHandler logs the user out of one of their sessions
+func (h *UserHandler) RevokeSessionHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeSessionSvc(ctx, userID, c.Param("id")); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error revoking session",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "session revoked successfully",
+	})
+}
+
+// LoginHistoryHandler lists the successful and failed logins on the account, paged with ?limit and ?offset
+func (h *UserHandler) LoginHistoryHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var page models.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "invalid query parameters",
+			"Error":   err.Error()})
+		return
+	}
+	if page.Limit <= 0 {
+		page.Limit = 10
+	}
+	if page.Limit > globals.MaxPageLimit {
+		page.Limit = globals.MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	history, err := h.service.ListLoginHistorySvc(ctx, userID, page.Limit, page.Offset)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error fetching login history",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "login history fetched successfully",
+		"Data":    history,
+	})
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+)
+
+type StatusHandler struct {
+	service interfaces.StatusServiceInterface
+}
+
+func NewStatusHandler(service interfaces.StatusServiceInterface) *StatusHandler {
+	return &StatusHandler{service: service}
+}
+
+func (h *StatusHandler) ListStatusesHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.service.ListStatusesSvc(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Status":  http.StatusInternalServerError,
+			"Message": "Error fetching statuses",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Statuses fetched successfully",
+		"Data":    statuses,
+	})
+}
+
+func (h *StatusHandler) CreateStatusHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	var status models.Status
+	if err := c.ShouldBindJSON(&status); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	status.UserID = userID
+
+	if err := h.service.CreateStatusSvc(ctx, &status); err != nil {
+		code := errorStatus(err)
+		c.JSON(code, gin.H{
+			"Status":  code,
+			"Message": "Error creating status",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"Status":  http.StatusCreated,
+		"Message": "Status created successfully",
+		"Data":    status,
+	})
+}
+
+// UpdateStatusHandler applies a merge patch to a status, absent fields are left unchanged
+func (h *StatusHandler) UpdateStatusHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid status ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var patch models.StatusPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	status, err := h.service.UpdateStatusSvc(ctx, id, userID, &patch)
+	if err != nil {
+		code := errorStatus(err)
+		c.JSON(code, gin.H{
+			"Status":  code,
+			"Message": "Error updating status",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Status updated successfully",
+		"Data":    status,
+	})
+}
+
+// SetTransitionsHandler replaces the statuses tasks may move to from a status, an empty list
+// allows moving to any status
+func (h *StatusHandler) SetTransitionsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid status ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var req models.Transitions
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	status, err := h.service.SetTransitionsSvc(ctx, id, userID, &req)
+	if err != nil {
+		code := errorStatus(err)
+		c.JSON(code, gin.H{
+			"Status":  code,
+			"Message": "Error setting status transitions",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Status transitions updated successfully",
+		"Data":    status,
+	})
+}
+
+// DeleteStatusHandler deletes a status, its tasks are moved to ?move_to=<status id> or to the
+// first remaining status of the same kind
+func (h *StatusHandler) DeleteStatusHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid status ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var opts models.DeleteStatus
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid query parameters",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.DeleteStatusSvc(ctx, id, userID, &opts); err != nil {
+		code := errorStatus(err)
+		c.JSON(code, gin.H{
+			"Status":  code,
+			"Message": "Error deleting status",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Status deleted successfully",
+	})
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+)
+
+type TagHandler struct {
+	service interfaces.TagServiceInterface
+}
+
+func NewTagHandler(service interfaces.TagServiceInterface) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+func (h *TagHandler) ListTagsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	tags, err := h.service.ListTagsSvc(ctx, userID)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching tags",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Tags fetched successfully",
+		"Data":    tags,
+	})
+}
+
+func (h *TagHandler) RenameTagHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	tagID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid tag ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var tag models.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.RenameTagSvc(ctx, tagID, userID, tag.Name); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error renaming tag",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Tag renamed successfully",
+	})
+}
+
+func (h *TagHandler) MergeTagsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	sourceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid tag ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var req models.MergeTags
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.MergeTagsSvc(ctx, sourceID, int(req.Into), userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error merging tags",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Tags merged successfully",
+	})
+}
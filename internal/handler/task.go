@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shivarajshanthaiah/todo-app/internal/models"
 	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
 )
 
 type TaskHandler struct {
@@ -51,8 +52,9 @@ func (h *TaskHandler) CreateTodoHandler(c *gin.Context) {
 	todo.UserID = userIDStr
 
 	if err := h.service.CreateTodoSvc(ctx, &todo); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"Status":  http.StatusInternalServerError,
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
 			"Message": "Error creating todo",
 			"Error":   err.Error(),
 		})
@@ -87,10 +89,22 @@ func (h *TaskHandler) GetTodosHandler(c *gin.Context) {
 	if req.Limit <= 0 {
 		req.Limit = 10
 	}
+	if req.Limit > globals.MaxPageLimit {
+		req.Limit = globals.MaxPageLimit
+	}
 	if req.Offset < 0 {
 		req.Offset = 0
 	}
 
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
 	userID, ok := c.Get("user_id")
 	if !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
@@ -107,10 +121,11 @@ func (h *TaskHandler) GetTodosHandler(c *gin.Context) {
 		return
 	}
 
-	todos, err := h.service.GetTodoByUserIDSvc(ctx, userIDStr, req.Status, req.Limit, req.Offset)
+	todos, err := h.service.GetTodoByUserIDSvc(ctx, userIDStr, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"Status":  http.StatusInternalServerError,
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
 			"Message": "Error fetching todos",
 			"Error":   err.Error(),
 		})
@@ -124,6 +139,149 @@ func (h *TaskHandler) GetTodosHandler(c *gin.Context) {
 	})
 }
 
+func (h *TaskHandler) GetTodoHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	todo, err := h.service.GetTodoByIDSvc(ctx, taskID, userID)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching todo",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", etag(todo.ID, todo.Version))
+	if noneMatch(c, todo.ID, todo.Version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todo fetched successfully",
+		"Data":    todo,
+	})
+}
+
+func (h *TaskHandler) SearchTodosHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	var req models.SearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	// Set defaults if not provided
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.Limit > globals.MaxPageLimit {
+		req.Limit = globals.MaxPageLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.service.SearchTodosSvc(ctx, userID, &req)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error searching todos",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todos searched successfully",
+		"Data":    results,
+	})
+}
+
+// BulkTodosHandler applies a list of create/update/delete/complete/move operations in one transaction
+func (h *TaskHandler) BulkTodosHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid bulk request",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	results, err := h.service.BulkTodosSvc(ctx, userID, &req)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error applying bulk operations",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	// 207 tells the client to look at the per-item results
+	status := http.StatusOK
+	if results.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, gin.H{
+		"Status":  status,
+		"Message": "Bulk operations processed",
+		"Data":    results,
+	})
+}
+
+// UpdateTodoHandler replaces a todo (PUT), fields missing from the body are cleared
 func (h *TaskHandler) UpdateTodoHandler(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c, 100*time.Second)
 	defer cancel()
@@ -163,18 +321,228 @@ func (h *TaskHandler) UpdateTodoHandler(c *gin.Context) {
 	todo.UserID = userIDStr
 	todo.ID = int64(id)
 
-	if err := h.service.UpdateTodoByIDSvc(ctx, &todo); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"Status":  http.StatusInternalServerError,
-			"Message": "Error updating todo",
+	// the version to check comes from If-Match only, never from the body
+	todo.Version, ok = ifMatchVersion(c, todo.ID)
+	if !ok {
+		return
+	}
+
+	// force=true completes the todo even while its blockers are pending
+	force := c.Query("force") == "true"
+
+	if err := h.service.UpdateTodoByIDSvc(ctx, &todo, force); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, errorBody(status, "Error updating todo", err))
+		return
+	}
+
+	c.Header("ETag", etag(todo.ID, todo.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todo updated successfully",
+	})
+}
+
+// PatchTodoHandler applies a JSON merge patch (RFC 7396) to a todo, only the fields present
+// in the body change and explicit nulls clear description and dueAt
+func (h *TaskHandler) PatchTodoHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var patch models.TodoPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
 			"Error":   err.Error(),
 		})
 		return
 	}
 
+	ifMatch, ok := ifMatchVersion(c, int64(taskID))
+	if !ok {
+		return
+	}
+
+	// force=true completes the todo even while its blockers are pending
+	force := c.Query("force") == "true"
+
+	todo, err := h.service.PatchTodoSvc(ctx, taskID, userID, ifMatch, force, &patch)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, errorBody(status, "Error updating todo", err))
+		return
+	}
+
+	c.Header("ETag", etag(todo.ID, todo.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"Status":  http.StatusOK,
 		"Message": "Todo updated successfully",
+		"Data":    todo,
+	})
+}
+
+// GetStatusHistoryHandler lists the status changes of a todo with its lead and cycle times
+func (h *TaskHandler) GetStatusHistoryHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	history, err := h.service.GetStatusHistorySvc(ctx, taskID, userID)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching todo status history",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todo status history fetched successfully",
+		"Data":    history,
+	})
+}
+
+// GetTodoHistoryHandler lists the revisions of a todo, newest first
+func (h *TaskHandler) GetTodoHistoryHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var page models.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid query parameters",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	if page.Limit <= 0 {
+		page.Limit = 10
+	}
+	if page.Limit > globals.MaxPageLimit {
+		page.Limit = globals.MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	history, err := h.service.GetTodoHistorySvc(ctx, taskID, userID, page.Limit, page.Offset)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching todo history",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todo history fetched successfully",
+		"Data":    history,
+	})
+}
+
+// RevertTodoHandler puts a todo back into the state of one of its revisions, the revert is
+// recorded as a new revision. If-Match and ?force=true work as on PATCH.
+func (h *TaskHandler) RevertTodoHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid revision",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	ifMatch, ok := ifMatchVersion(c, int64(taskID))
+	if !ok {
+		return
+	}
+
+	// force=true completes the todo even while its blockers are pending
+	force := c.Query("force") == "true"
+
+	todo, err := h.service.RevertTodoSvc(ctx, taskID, revision, userID, ifMatch, force)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, errorBody(status, "Error reverting todo", err))
+		return
+	}
+
+	c.Header("ETag", etag(todo.ID, todo.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todo reverted successfully",
+		"Data":    todo,
 	})
 }
 
@@ -208,9 +576,15 @@ func (h *TaskHandler) DeleteTodoHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteTodoByIDSvc(ctx, taskID, userIDStr); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"Status":  http.StatusInternalServerError,
+	ifMatch, ok := ifMatchVersion(c, int64(taskID))
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTodoByIDSvc(ctx, taskID, userIDStr, ifMatch); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
 			"Message": "Error deleting todo",
 			"Error":   err.Error(),
 		})
@@ -222,3 +596,300 @@ func (h *TaskHandler) DeleteTodoHandler(c *gin.Context) {
 		"Message": "Todo deleted successfully",
 	})
 }
+
+// AddDependencyHandler marks a todo as blocked by another todo
+func (h *TaskHandler) AddDependencyHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var dep models.Dependency
+	if err := c.ShouldBindJSON(&dep); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.AddDependencySvc(ctx, taskID, int(dep.BlockedBy), userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error adding dependency",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"Status":  http.StatusCreated,
+		"Message": "Dependency added successfully",
+	})
+}
+
+// RemoveDependencyHandler removes a blocked-by relationship from a todo
+func (h *TaskHandler) RemoveDependencyHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	blockerID, err := strconv.Atoi(c.Param("blocker_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid blocker task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.RemoveDependencySvc(ctx, taskID, blockerID, userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error removing dependency",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Dependency removed successfully",
+	})
+}
+
+func (h *TaskHandler) UpdateRecurrenceHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	var req models.Recurrence
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Error binding request body",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	ifMatch, ok := ifMatchVersion(c, int64(taskID))
+	if !ok {
+		return
+	}
+
+	if err := h.service.UpdateRecurrenceSvc(ctx, taskID, userID, req.Rule, ifMatch); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error updating recurrence",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Recurrence updated successfully",
+	})
+}
+
+func (h *TaskHandler) CancelRecurrenceHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	ifMatch, ok := ifMatchVersion(c, int64(taskID))
+	if !ok {
+		return
+	}
+
+	if err := h.service.UpdateRecurrenceSvc(ctx, taskID, userID, "", ifMatch); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error cancelling recurrence",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Recurrence cancelled successfully",
+	})
+}
+
+func (h *TaskHandler) ListTrashHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var page models.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid query parameters",
+			"Error":   err.Error(),
+		})
+		return
+	}
+	if page.Limit <= 0 {
+		page.Limit = 10
+	}
+	if page.Limit > globals.MaxPageLimit {
+		page.Limit = globals.MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	todos, err := h.service.ListTrashSvc(ctx, userID, page.Limit, page.Offset)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error fetching trash",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Trash fetched successfully",
+		"Data":    todos,
+	})
+}
+
+func (h *TaskHandler) RestoreTodoHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.RestoreTodoSvc(ctx, taskID, userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error restoring todo",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todo restored successfully",
+	})
+}
+
+func (h *TaskHandler) PurgeTodoHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 100*time.Second)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Status":  http.StatusBadRequest,
+			"Message": "Invalid task ID",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.PurgeTodoSvc(ctx, taskID, userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{
+			"Status":  status,
+			"Message": "Error purging todo",
+			"Error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "Todo permanently deleted",
+	})
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+)
+
+// LoginTwoFactorHandler exchanges a login challenge and a TOTP or recovery code for a token pair
+func (h *UserHandler) LoginTwoFactorHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	var req models.TwoFactorLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	tokens, err := h.service.LoginTwoFactorSvc(ctx, &req, clientInfo(c))
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error in two-factor login",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"Status":  http.StatusAccepted,
+		"Message": "user logged in successfully",
+		"Data":    tokens,
+	})
+}
+
+// EnrollTwoFactorHandler returns a new TOTP secret and its otpauth URI for the authenticator app
+func (h *UserHandler) EnrollTwoFactorHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.service.EnrollTwoFactorSvc(ctx, userID)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error enrolling in two-factor authentication",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "scan the otpauth URI and confirm with a code to turn on two-factor authentication",
+		"Data":    enrollment,
+	})
+}
+
+// ConfirmTwoFactorHandler turns on two-factor authentication with a code of the enrolled secret
+func (h *UserHandler) ConfirmTwoFactorHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.TwoFactorCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	codes, err := h.service.ConfirmTwoFactorSvc(ctx, userID, req.Code)
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error confirming two-factor authentication",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "two-factor authentication enabled, store the recovery codes safely, they are not shown again",
+		"Data":    codes,
+	})
+}
+
+// DisableTwoFactorHandler turns off two-factor authentication after checking the password
+func (h *UserHandler) DisableTwoFactorHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.TwoFactorDisable
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	if err := h.service.DisableTwoFactorSvc(ctx, userID, req.Password); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error disabling two-factor authentication",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "two-factor authentication disabled",
+	})
+}
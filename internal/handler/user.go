@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shivarajshanthaiah/todo-app/internal/models"
 	"github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
 )
 
 type UserHandler struct {
@@ -36,7 +38,8 @@ func (h *UserHandler) UserSignUpHandler(c *gin.Context) {
 
 	if err := h.service.UserSignUpSvc(ctx, &user); err != nil {
 		log.Printf("UserSignUpHandler Error in signup service: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"Status": http.StatusInternalServerError,
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
 			"Message": "error in signup service",
 			"Error":   err.Error()})
 		return
@@ -63,17 +66,103 @@ func (h *UserHandler) UserLoginHandler(c *gin.Context) {
 		return
 	}
 
-	token, err := h.service.UserLoginSvc(ctx, &user)
+	tokens, challenge, err := h.service.UserLoginSvc(ctx, &user, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+		status := http.StatusBadRequest
+		if errors.Is(err, globals.ErrEmailNotVerified) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"Status": status,
 			"Message": "error in login service",
 			"Error":   err.Error()})
 		return
 	}
+	if challenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"Status":  http.StatusOK,
+			"Message": "two-factor authentication required, send a code to /login/2fa",
+			"Data":    challenge,
+		})
+		return
+	}
 	c.JSON(http.StatusAccepted, gin.H{
 		"Status":  http.StatusAccepted,
 		"Message": "user logged in successfully",
-		"Data":    token,
+		"Data":    tokens,
+	})
+}
+
+// RefreshTokenHandler exchanges a refresh token for a new access and refresh token
+func (h *UserHandler) RefreshTokenHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	tokens, err := h.service.RefreshTokenSvc(ctx, req.RefreshToken, clientInfo(c))
+	if err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error refreshing token",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "token refreshed successfully",
+		"Data":    tokens,
+	})
+}
+
+// LogoutHandler revokes the access token of the request and the refresh tokens of its login
+func (h *UserHandler) LogoutHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.LogoutSvc(ctx, userID, c.GetString("jti"), c.GetTime("token_expires_at")); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error logging out",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "user logged out successfully",
+	})
+}
+
+// LogoutAllHandler revokes the tokens of every device the user is logged in on
+func (h *UserHandler) LogoutAllHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.LogoutAllSvc(ctx, userID); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error logging out of all devices",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "user logged out of all devices successfully",
 	})
 }
 
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+)
+
+// VerifyEmailHandler confirms the email of an account with a mailed verification token
+func (h *UserHandler) VerifyEmailHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	var req models.VerifyEmail
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	if err := h.service.VerifyEmailSvc(ctx, req.Token); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error verifying email",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Status":  http.StatusOK,
+		"Message": "email verified successfully",
+	})
+}
+
+// ResendVerificationHandler mails a new verification token. It answers the same whether or
+// not an unverified account exists for the email.
+func (h *UserHandler) ResendVerificationHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	var req models.ResendVerification
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+			"Message": "error in binding data",
+			"Error":   err.Error()})
+		return
+	}
+
+	if err := h.service.ResendVerificationSvc(ctx, req.Email); err != nil {
+		status := errorStatus(err)
+		c.JSON(status, gin.H{"Status": status,
+			"Message": "error resending verification email",
+			"Error":   err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"Status":  http.StatusAccepted,
+		"Message": "if an unverified account exists for this email, a verification link has been sent",
+	})
+}
+
+// RequireVerifiedEmail runs before the handlers that change tasks, projects, statuses or tags and
+// stops the request when REQUIRE_VERIFIED_TASKS is set and the user has not verified their email
+func (h *UserHandler) RequireVerifiedEmail(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, time.Second*100)
+	defer cancel()
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		c.Abort()
+		return
+	}
+
+	if err := h.service.EnsureEmailVerifiedSvc(ctx, userID); err != nil {
+		status := errorStatus(err)
+		c.AbortWithStatusJSON(status, gin.H{"Status": status,
+			"Message": "verify your email before making changes",
+			"Error":   err.Error()})
+		return
+	}
+	c.Next()
+}
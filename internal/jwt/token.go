@@ -1,10 +1,15 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 )
 
 type Claims struct {
@@ -13,17 +18,20 @@ type Claims struct {
 	jwt.StandardClaims
 }
 
-// GenerateToken will generate token for 5 hours with given data
-func GenerateToken(key, email string, userID string) (string, error) {
-	expTime := time.Now().Add(time.Hour * 5).Unix()
+// GenerateToken will generate an access token valid for ttl with given data. Every token gets
+// a unique id in the jti claim so it can be revoked before it expires, the claims are returned
+// along with the signed token.
+func GenerateToken(key, email string, userID string, ttl time.Duration) (string, *Claims, error) {
+	now := time.Now()
 
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expTime,
+			Id:        uuid.NewString(),
+			ExpiresAt: now.Add(ttl).Unix(),
 			Subject:   email,
-			IssuedAt:  time.Now().Unix(),
+			IssuedAt:  now.Unix(),
 		},
 	}
 
@@ -31,7 +39,25 @@ func GenerateToken(key, email string, userID string) (string, error) {
 	signedToken, err := jwtToken.SignedString([]byte(key))
 	if err != nil {
 		log.Printf("unable to generate token for user %v, err: %v", email, err.Error())
-		return "", err
+		return "", nil, err
 	}
-	return signedToken, nil
+	return signedToken, claims, nil
+}
+
+// GenerateOpaqueToken returns a random URL-safe token together with the hash to store instead
+// of it, used for refresh tokens and other secrets handed out to clients
+func GenerateOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken returns the hex SHA-256 of a token. The tokens are random, so a fast hash is
+// enough to keep them useless if the database leaks.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -1,14 +1,20 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	redisCl "github.com/shivarajshanthaiah/todo-app/internal/clients/redis"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
 )
 
-func Authorization(key string) gin.HandlerFunc {
+// Authorization accepts requests with a valid access token that has not been revoked, revoked
+// tokens are kept in the redis denylist under their jti until they expire.
+func Authorization(key string, denylist *redisCl.RedisService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenString := ctx.GetHeader("Authorization")
 
@@ -64,8 +70,42 @@ func Authorization(key string) gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
+
+		jti, ok := claims["jti"].(string)
+		if !ok || jti == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"Status": "Failed",
+				"Message": "Token id not found in token",
+				"Data":    "",
+				"Error":   ok})
+			ctx.Abort()
+			return
+		}
+		revoked, err := denylist.ExistsInRedis(globals.TokenDenylistPrefix + jti)
+		if err != nil {
+			log.Printf("Error checking token denylist: %v", err)
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"Status": "Failed",
+				"Message": "Unable to verify token",
+				"Data":    "",
+				"Error":   err.Error()})
+			ctx.Abort()
+			return
+		}
+		if revoked {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"Status": "Failed",
+				"Message": "Token has been revoked",
+				"Data":    "",
+				"Error":   "revoked token"})
+			ctx.Abort()
+			return
+		}
+
+		// tokens issued by jwt.GenerateToken always carry an expiry
+		exp, _ := claims["exp"].(float64)
+
 		ctx.Set("email", email)
 		ctx.Set("user_id", userID)
+		ctx.Set("jti", jti)
+		ctx.Set("token_expires_at", time.Unix(int64(exp), 0))
 		ctx.Next()
 	}
 }
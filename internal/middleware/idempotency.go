@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	redisCl "github.com/shivarajshanthaiah/todo-app/internal/clients/redis"
+)
+
+// IdempotencyKeyHeader is the request header carrying the client generated key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds the keys stored in redis
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders are the response headers stored with a response and sent again on replay,
+// clients need the ETag of a write for their next If-Match
+var replayedHeaders = []string{"ETag", "Location"}
+
+// idempotencyRecord is what is kept in redis for a key. A record without a status belongs to a
+// request that is still being processed.
+type idempotencyRecord struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// responseRecorder keeps a copy of the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes POST, PATCH and DELETE requests carrying an Idempotency-Key header safe to
+// retry. The first response for a key is stored for the given window and replayed on repeats,
+// a repeat with a different method, path or body is rejected with 422. It must run after
+// Authorization since keys are scoped per user. The body is buffered to fingerprint it, bodies
+// over maxBody bytes are rejected with 413 before they are read into memory.
+func Idempotency(store *redisCl.RedisService, window time.Duration, maxBody int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		method := ctx.Request.Method
+		if key == "" || (method != http.MethodPost && method != http.MethodPatch && method != http.MethodDelete) {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			ctx.JSON(http.StatusBadRequest, gin.H{"Status": http.StatusBadRequest,
+				"Message": "Idempotency-Key is too long",
+				"Error":   ""})
+			ctx.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBody))
+		if err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			ctx.JSON(status, gin.H{"Status": status,
+				"Message": "Error reading request body",
+				"Error":   err.Error()})
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256([]byte(method + " " + ctx.Request.URL.RequestURI() + "\n" + string(body)))
+		fingerprint := hex.EncodeToString(sum[:])
+		redisKey := "idempotency:" + ctx.GetString("user_id") + ":" + key
+
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		reserved, err := store.SetIfAbsentInRedis(redisKey, pending, window)
+		if err != nil {
+			// without redis the request is processed as if no key was sent
+			log.Printf("Error reserving idempotency key: %v", err)
+			ctx.Next()
+			return
+		}
+		if !reserved {
+			replayIdempotent(ctx, store, redisKey, fingerprint)
+			return
+		}
+
+		// a panicking handler never answered, release the key so the client can retry
+		defer func() {
+			if r := recover(); r != nil {
+				if err := store.DeleteFromRedis(redisKey); err != nil {
+					log.Printf("Error releasing idempotency key: %v", err)
+				}
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			// server errors are not final, let the client retry with the same key
+			if err := store.DeleteFromRedis(redisKey); err != nil {
+				log.Printf("Error releasing idempotency key: %v", err)
+			}
+			return
+		}
+
+		headers := make(map[string]string)
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+		record, _ := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Headers:     headers,
+			Body:        recorder.body.Bytes(),
+		})
+		if err := store.SetDataInRedis(redisKey, record, window); err != nil {
+			log.Printf("Error storing idempotent response: %v", err)
+		}
+	}
+}
+
+// replayIdempotent answers a repeated key with the stored response
+func replayIdempotent(ctx *gin.Context, store *redisCl.RedisService, redisKey, fingerprint string) {
+	defer ctx.Abort()
+
+	data, err := store.GetFromRedis(redisKey)
+	if err == redis.Nil {
+		// the key expired in between, the client may simply retry
+		ctx.JSON(http.StatusConflict, gin.H{"Status": http.StatusConflict,
+			"Message": "Idempotency-Key expired while checking it, retry the request",
+			"Error":   ""})
+		return
+	}
+	var record idempotencyRecord
+	if err == nil {
+		err = json.Unmarshal([]byte(data), &record)
+	}
+	if err != nil {
+		log.Printf("Error reading idempotency key: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"Status": http.StatusInternalServerError,
+			"Message": "Error reading Idempotency-Key",
+			"Error":   err.Error()})
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"Status": http.StatusUnprocessableEntity,
+			"Message": "Idempotency-Key was already used with a different request",
+			"Error":   ""})
+		return
+	}
+	if record.Status == 0 {
+		ctx.JSON(http.StatusConflict, gin.H{"Status": http.StatusConflict,
+			"Message": "A request with this Idempotency-Key is still being processed",
+			"Error":   ""})
+		return
+	}
+
+	for name, value := range record.Headers {
+		ctx.Header(name, value)
+	}
+	ctx.Header("Idempotent-Replayed", "true")
+	ctx.Data(record.Status, record.ContentType, record.Body)
+}
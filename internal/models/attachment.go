@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Attachment struct represents a file uploaded to a todo
+type Attachment struct {
+	ID          int64     `json:"id"`
+	TodoID      int64     `json:"todo_id"`
+	UserID      string    `json:"user_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Created     time.Time `json:"created"`
+}
+
+// AttachmentList holds the attachments of a todo and how much of their quota the user has used
+type AttachmentList struct {
+	Attachments []*Attachment `json:"attachments"`
+	UsedBytes   int64         `json:"used_bytes"`
+	QuotaBytes  int64         `json:"quota_bytes"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxBulkOperations limits how many operations a single bulk request can carry
+const maxBulkOperations = 100
+
+// Operations accepted by the bulk endpoint
+const (
+	BulkCreate   = "create"
+	BulkUpdate   = "update"
+	BulkDelete   = "delete"
+	BulkComplete = "complete"
+	BulkMove     = "move"
+)
+
+// BulkRequest struct represents a list of operations applied in one transaction.
+// With Atomic set either every operation is applied or none, otherwise each one succeeds or fails on its own.
+type BulkRequest struct {
+	Atomic     bool            `json:"atomic"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+// BulkOperation struct represents one operation of a bulk request
+type BulkOperation struct {
+	Op        string     `json:"op"`                   // "create", "update", "delete", "complete" or "move"
+	ID        int64      `json:"id,omitempty"`         // the task to change, unused by create
+	Version   int64      `json:"version,omitempty"`    // optional, the operation fails unless the task is at this version
+	Todo      *Todo      `json:"todo,omitempty"`       // the task to create
+	Patch     *TodoPatch `json:"patch,omitempty"`      // merge patch applied by update
+	ProjectID *int64     `json:"project_id,omitempty"` // target project of move, the Inbox when empty
+	Force     bool       `json:"force,omitempty"`      // complete the task even if it is blocked
+}
+
+// BulkResult struct represents the outcome of one operation, in the order of the request
+type BulkResult struct {
+	Index   int      `json:"index"`
+	Op      string   `json:"op"`
+	ID      int64    `json:"id,omitempty"`
+	Success bool     `json:"success"`
+	Error   string   `json:"error,omitempty"`
+	Allowed []string `json:"allowed,omitempty"` // next statuses the todo may move to, on an illegal transition
+	Todo    *Todo    `json:"todo,omitempty"`
+}
+
+type BulkResults struct {
+	Atomic    bool          `json:"atomic"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []*BulkResult `json:"results"`
+}
+
+// Validate checks the shape of the operations, the values are checked when they are applied
+func (r *BulkRequest) Validate() error {
+	if len(r.Operations) == 0 {
+		return errors.New("at least one operation is required")
+	}
+	if len(r.Operations) > maxBulkOperations {
+		return fmt.Errorf("at most %d operations are allowed", maxBulkOperations)
+	}
+
+	for i, op := range r.Operations {
+		switch op.Op {
+		case BulkCreate:
+			if op.Todo == nil {
+				return fmt.Errorf("operation %d: todo is required", i)
+			}
+		case BulkUpdate:
+			if op.Patch == nil {
+				return fmt.Errorf("operation %d: patch is required", i)
+			}
+		case BulkDelete, BulkComplete, BulkMove:
+		default:
+			return fmt.Errorf("operation %d: invalid op %q", i, op.Op)
+		}
+		if op.Op != BulkCreate && op.ID <= 0 {
+			return fmt.Errorf("operation %d: id is required", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ChecklistItem struct represents a step of a todo that is ticked off without being a subtask
+type ChecklistItem struct {
+	ID       int64     `json:"id"`
+	TodoID   int64     `json:"todo_id"`
+	Text     string    `json:"text" binding:"required"`
+	Done     bool      `json:"done"`
+	Position int       `json:"position"`
+	Created  time.Time `json:"created"`
+	Updated  time.Time `json:"updated"`
+}
+
+// ChecklistItemPatch changes the fields of an item that are present
+type ChecklistItemPatch struct {
+	Text *string `json:"text"`
+	Done *bool   `json:"done"`
+}
+
+// ChecklistOrder lists every item of a checklist in its new order
+type ChecklistOrder struct {
+	ItemIDs []int64 `json:"item_ids" binding:"required"`
+}
+
+// ChecklistProgress tells how many of the checklist items of a todo are done, e.g. 3/5
+type ChecklistProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Checklist is the checklist of a todo after a change
+type Checklist struct {
+	TodoID   int64             `json:"todo_id"`
+	Items    []*ChecklistItem  `json:"items"`
+	Progress ChecklistProgress `json:"progress"`
+	// TaskCompleted is set when checking the last item completed the todo
+	TaskCompleted bool `json:"task_completed,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Comment struct represents a note left on a todo
+type Comment struct {
+	ID      int64     `json:"id"`
+	TodoID  int64     `json:"todo_id"`
+	UserID  string    `json:"user_id"`
+	Body    string    `json:"body" binding:"required"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+type PaginatedComments struct {
+	TotalCount int64      `json:"total_count"`
+	Comments   []*Comment `json:"comments"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Field is a member of a JSON merge patch. Set reports whether the key was present in the
+// document and Null whether it was explicitly null, Value holds the decoded value otherwise.
+type Field[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// TodoPatch struct represents a merge patch (RFC 7396) for a todo, absent fields are left unchanged
+type TodoPatch struct {
+	Title       Field[string]    `json:"title"`
+	Description Field[string]    `json:"description"` // null clears it
+	Priority    Field[string]    `json:"priority"`
+	Status      Field[string]    `json:"status"`
+	DueAt       Field[time.Time] `json:"dueAt"` // null clears it
+	ProjectID   Field[int64]     `json:"project_id"`
+	Tags        Field[[]string]  `json:"tags"` // null or [] clears them
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Project struct represents a list that groups the user's todos
+type Project struct {
+	ID        int64     `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	IsDefault bool      `json:"is_default"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// DeleteProject describes what happens to the tasks of a deleted project.
+// Tasks are moved to MoveTo, or to the user's Inbox when it is empty. With DeleteTasks they are
+// moved to the trash instead and come back in the Inbox when restored.
+type DeleteProject struct {
+	DeleteTasks bool   `form:"delete_tasks"`
+	MoveTo      *int64 `form:"move_to"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Revision struct represents one recorded change of a todo. Changes is keyed by the todo
+// field names and holds the value before and after the change.
+type Revision struct {
+	Revision   int                    `json:"revision"`
+	Action     string                 `json:"action"` // "create", "update", "delete", "restore" or "revert"
+	ActorID    string                 `json:"actor_id"`
+	RevertedTo *int                   `json:"reverted_to,omitempty"` // the revision a revert went back to
+	Changes    map[string]FieldChange `json:"changes"`
+	Created    time.Time              `json:"created"`
+}
+
+// FieldChange struct represents the old and new value of a changed field
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+type PaginatedRevisions struct {
+	TotalCount int64       `json:"total_count"`
+	Revisions  []*Revision `json:"revisions"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// SearchRequest struct represents a full-text search over the user's todos
+type SearchRequest struct {
+	Query  string `json:"query" binding:"required"` // web search syntax: words, "phrases", OR, -exclude
+	Status string `json:"status"`                   // "ALL" or the name of one of the user's statuses
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// SearchResult struct represents a matched todo, snippets wrap matched words in <mark></mark>
+type SearchResult struct {
+	Todo               *Todo   `json:"todo"`
+	Rank               float64 `json:"rank"`
+	TitleSnippet       string  `json:"title_snippet"`
+	DescriptionSnippet string  `json:"description_snippet"`
+}
+
+type SearchResults struct {
+	TotalCount int64           `json:"total_count"`
+	Results    []*SearchResult `json:"results"`
+}
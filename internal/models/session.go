@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Client describes the device a request comes from
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// Session struct represents a login of the user on a device
+type Session struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	Created   time.Time `json:"created"`
+	LastSeen  time.Time `json:"last_seen"` // last login or token refresh
+	Expires   time.Time `json:"expires"`
+	Current   bool      `json:"current"` // the session of the token making the request
+}
+
+// LoginAttempt struct represents a successful or failed login on the user's account
+type LoginAttempt struct {
+	ID        int64     `json:"id"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"` // why a failed attempt was refused
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	Created   time.Time `json:"created"`
+}
+
+type PaginatedLoginAttempts struct {
+	TotalCount int64           `json:"total_count"`
+	Attempts   []*LoginAttempt `json:"attempts"`
+}
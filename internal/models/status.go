@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Status struct represents a step of the user's workflow, tasks in a terminal status count as done
+type Status struct {
+	ID          int64     `json:"id"`
+	UserID      string    `json:"user_id"`
+	Name        string    `json:"name"`
+	Position    int       `json:"position"` // order of the status in the workflow, appended when 0
+	Color       string    `json:"color"`    // "#rrggbb"
+	IsTerminal  bool      `json:"is_terminal"`
+	Transitions []string  `json:"transitions"` // statuses a task may move to next, empty allows any
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+// StatusPatch struct represents a merge patch for a status, absent fields are left unchanged
+type StatusPatch struct {
+	Name       Field[string] `json:"name"`
+	Position   Field[int]    `json:"position"`
+	Color      Field[string] `json:"color"`
+	IsTerminal Field[bool]   `json:"is_terminal"`
+}
+
+// DeleteStatus describes where the tasks of a deleted status go. Without MoveTo they go to the
+// first remaining status of the same kind (terminal or not).
+type DeleteStatus struct {
+	MoveTo *int64 `form:"move_to"`
+}
+
+// Transitions struct represents the statuses a task may move to from a status, an empty list
+// lifts the restriction
+type Transitions struct {
+	To []string `json:"to"`
+}
+
+// StatusChange struct represents one move of a todo into a status, From is empty for the
+// status the todo was created in
+type StatusChange struct {
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// StatusHistory struct represents the status changes of a todo. Lead time runs from creation
+// and cycle time from the first move out of the starting status, both until the todo was last
+// completed. They are only set while the todo is done.
+type StatusHistory struct {
+	TodoID           int64           `json:"todo_id"`
+	Changes          []*StatusChange `json:"changes"`
+	LeadTimeSeconds  *int64          `json:"lead_time_seconds,omitempty"`
+	CycleTimeSeconds *int64          `json:"cycle_time_seconds,omitempty"`
+}
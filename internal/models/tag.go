@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Tag struct represents a label attached to todos
+type Tag struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	UsageCount int64     `json:"usage_count"`
+	Created    time.Time `json:"created"`
+}
+
+// MergeTags struct represents the tag another tag is merged into
+type MergeTags struct {
+	Into int64 `json:"into" binding:"required"`
+}
@@ -1,28 +1,150 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
 
 // Todo struct represents the todo list data
 type Todo struct {
-	ID          int64     `json:"id"`
-	UserID      string    `json:"user_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Priority    string    `json:"priority"`
-	Status      string    `json:"status"`
-	DueAt       time.Time `json:"dueAt"`
-	Created     time.Time `json:"created"`
-	Updated     time.Time `json:"updated"`
+	ID          int64              `json:"id"`
+	UserID      string             `json:"user_id"`
+	ParentID    *int64             `json:"parent_id,omitempty"`
+	ProjectID   *int64             `json:"project_id,omitempty"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Priority    string             `json:"priority"`
+	Status      string             `json:"status"` // name of one of the user's statuses, defaults to the first open one
+	DueAt       time.Time          `json:"dueAt"`
+	Recurrence  string             `json:"recurrence,omitempty"` // RRULE, e.g. "FREQ=WEEKLY;BYDAY=MO"
+	Version     int64              `json:"version"`              // also sent as the ETag header
+	Created     time.Time          `json:"created"`
+	Updated     time.Time          `json:"updated"`
+	Tags        []string           `json:"tags"`
+	BlockedBy   []int64            `json:"blocked_by,omitempty"` // ids of the tasks this one waits on
+	Blocked     bool               `json:"blocked"`              // true while one of BlockedBy is not done
+	Comments    int64              `json:"comment_count"`
+	Checklist   *ChecklistProgress `json:"checklist,omitempty"`       // left out when the todo has no checklist
+	Items       []*ChecklistItem   `json:"checklist_items,omitempty"` // only filled when fetching a single todo
+	Subtasks    []*Todo            `json:"subtasks,omitempty"`
 }
 
 type PaginatedTodos struct {
-	TotalCount int64   `json:"total_count"`
+	TotalCount *int64  `json:"total_count,omitempty"`
 	Todos      []*Todo `json:"todos"`
+	NextCursor string  `json:"next_cursor,omitempty"` // pass as "cursor" to read the following page
+	PrevCursor string  `json:"prev_cursor,omitempty"` // pass as "cursor" to read the preceding page
 }
 
 type Request struct {
 	// UserID string `json:"user_id" binding:"required"`
-	Status string `json:"status"` // "ALL", "PENDING", "COMPLETED"
-	Limit  int    `json:"limit" default:"10"`
-	Offset int    `json:"offset" default:"0"`
+	Status     string   `json:"status"`     // "ALL" or the name of one of the user's statuses
+	Priorities []string `json:"priorities"` // any of "LOW", "MEDIUM", "HIGH"
+	ParentID   *int64   `json:"parent_id"`  // only list the direct subtasks of this task
+	ProjectID  *int64   `json:"project_id"` // only list the tasks of this project
+	TagsAny    []string `json:"tags_any"`   // tasks having at least one of these tags
+	TagsAll    []string `json:"tags_all"`   // tasks having every one of these tags
+	Tree       bool     `json:"tree"`       // list top level tasks with their subtasks nested
+	Actionable bool     `json:"actionable"` // open tasks that are not blocked by an open task
+
+	DueFrom     *time.Time `json:"due_from"`
+	DueTo       *time.Time `json:"due_to"`
+	Overdue     bool       `json:"overdue"`     // due in the past and not completed
+	NoDueDate   bool       `json:"no_due_date"` // tasks without a due date only
+	CreatedFrom *time.Time `json:"created_from"`
+	CreatedTo   *time.Time `json:"created_to"`
+	UpdatedFrom *time.Time `json:"updated_from"`
+	UpdatedTo   *time.Time `json:"updated_to"`
+
+	Sort []SortField `json:"sort"` // applied in order, defaults to created_at desc
+
+	// Cursor pagination is preferred, offset is only used when no cursor is given.
+	// The total count is returned by default on offset pages and on request on cursor pages.
+	Cursor       string `json:"cursor"`
+	IncludeTotal *bool  `json:"include_total"`
+	Limit        int    `json:"limit" default:"10"`
+	Offset       int    `json:"offset" default:"0"`
+}
+
+// SortField struct represents one key of a multi-field sort
+type SortField struct {
+	Field     string `json:"field"`     // "due_at", "priority", "title", "created_at", "updated_at"
+	Direction string `json:"direction"` // "asc" or "desc", defaults to "asc"
+}
+
+// maxSortFields limits how many sort keys a single list request can use
+const maxSortFields = 5
+
+// Validate checks the filters and sort keys of a list request
+func (r *Request) Validate() error {
+	for _, priority := range r.Priorities {
+		if _, ok := globals.TaskPriority[priority]; !ok {
+			return fmt.Errorf("invalid priority filter: %s", priority)
+		}
+	}
+
+	if err := validateRange("due", r.DueFrom, r.DueTo); err != nil {
+		return err
+	}
+	if err := validateRange("created", r.CreatedFrom, r.CreatedTo); err != nil {
+		return err
+	}
+	if err := validateRange("updated", r.UpdatedFrom, r.UpdatedTo); err != nil {
+		return err
+	}
+	if r.NoDueDate && (r.DueFrom != nil || r.DueTo != nil || r.Overdue) {
+		return errors.New("no_due_date cannot be combined with due date filters")
+	}
+
+	if len(r.Sort) > maxSortFields {
+		return fmt.Errorf("at most %d sort fields are allowed", maxSortFields)
+	}
+	seen := make(map[string]bool, len(r.Sort))
+	for i := range r.Sort {
+		sort := &r.Sort[i]
+		sort.Field = strings.ToLower(sort.Field)
+		sort.Direction = strings.ToLower(sort.Direction)
+		if !globals.TaskSortFields[sort.Field] {
+			return fmt.Errorf("invalid sort field: %s", sort.Field)
+		}
+		if seen[sort.Field] {
+			return fmt.Errorf("duplicate sort field: %s", sort.Field)
+		}
+		seen[sort.Field] = true
+		switch sort.Direction {
+		case "":
+			sort.Direction = "asc"
+		case "asc", "desc":
+		default:
+			return fmt.Errorf("invalid sort direction: %s", sort.Direction)
+		}
+	}
+	return nil
+}
+
+func validateRange(name string, from, to *time.Time) error {
+	if from != nil && to != nil && from.After(*to) {
+		return fmt.Errorf("%s_from must not be after %s_to", name, name)
+	}
+	return nil
+}
+
+// Dependency struct represents a blocked-by relationship to add to a todo
+type Dependency struct {
+	BlockedBy int64 `json:"blocked_by" binding:"required"`
+}
+
+// Pagination struct represents limit/offset paging read from the query string
+type Pagination struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// Recurrence struct represents the repeat rule of a todo
+type Recurrence struct {
+	Rule string `json:"rule" binding:"required"`
 }
@@ -0,0 +1,37 @@
+package models
+
+// TwoFactorEnrollment is the secret of a pending two-factor enrollment. The URI is shown as a
+// QR code for authenticator apps, the secret can be typed in instead.
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// TwoFactorCode carries a code from the authenticator app
+type TwoFactorCode struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorDisable confirms turning two-factor authentication off with the account password
+type TwoFactorDisable struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// RecoveryCodes are shown once when two-factor authentication is turned on, each one can log
+// in once in place of a code from the authenticator app
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// LoginChallenge is returned by the login of an account with two-factor authentication instead
+// of a token pair, it is exchanged for one together with a code at /login/2fa
+type LoginChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int64  `json:"expires_in"` // seconds until the challenge expires
+}
+
+// TwoFactorLogin completes a login with the challenge token and a TOTP or recovery code
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
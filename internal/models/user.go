@@ -6,6 +6,9 @@ type User struct {
 	UserName string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+
+	EmailVerified    bool `json:"email_verified"`
+	TwoFactorEnabled bool `json:"two_factor_enabled"`
 }
 
 // Login struct represents the user login data
@@ -13,3 +16,38 @@ type Login struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// TokenPair is returned on login and refresh. The access token goes in the Authorization
+// header, the refresh token is exchanged for a new pair once it expires.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // seconds until the access token expires
+}
+
+// RefreshRequest carries the refresh token to exchange for a new pair
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ForgotPassword asks for a password reset token to be mailed to the email
+type ForgotPassword struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// ResetPassword sets a new password with a mailed reset token
+type ResetPassword struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// VerifyEmail confirms the email of an account with a mailed verification token
+type VerifyEmail struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerification asks for a new verification token to be mailed to the email
+type ResendVerification struct {
+	Email string `json:"email" binding:"required"`
+}
@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+const attachmentColumns = `id, task_id, user_id, filename, content_type, size, storage_key, created_at`
+
+type AttachmentRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewAttachmentRepository(dao *pgxpool.Pool) interfaces.AttachmentRepoInterface {
+	return &AttachmentRepo{
+		dao: dao,
+	}
+}
+
+func scanAttachment(row pgx.Row) (*entity.Attachment, error) {
+	var (
+		id, taskID, size                   sql.NullInt64
+		userID, filename, contentType, key sql.NullString
+		createdAt                          sql.NullTime
+	)
+
+	if err := row.Scan(&id, &taskID, &userID, &filename, &contentType, &size, &key, &createdAt); err != nil {
+		return nil, err
+	}
+
+	attachment := &entity.Attachment{
+		ID:          id.Int64,
+		UserID:      userID.String,
+		Filename:    filename.String,
+		ContentType: contentType.String,
+		Size:        size.Int64,
+		StorageKey:  key.String,
+		CreatedAt:   createdAt.Time,
+	}
+	if taskID.Valid {
+		attachment.TaskID = &taskID.Int64
+	}
+	return attachment, nil
+}
+
+func scanAttachments(rows pgx.Rows) ([]*entity.Attachment, error) {
+	defer rows.Close()
+
+	var attachments []*entity.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// CreateAttachment stores an attachment only if the files of its user, this one included, stay
+// within quota bytes. It returns pgx.ErrNoRows when the quota would be exceeded.
+func (r *AttachmentRepo) CreateAttachment(ctx context.Context, attachment *entity.Attachment, quota int64) error {
+	query := `
+		INSERT INTO attachments (task_id, user_id, filename, content_type, size, storage_key)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE (SELECT COALESCE(SUM(size), 0) FROM attachments WHERE user_id = $2) + $5 <= $7
+		RETURNING id, created_at
+	`
+
+	return r.dao.QueryRow(ctx, query, attachment.TaskID, attachment.UserID, attachment.Filename,
+		attachment.ContentType, attachment.Size, attachment.StorageKey, quota).
+		Scan(&attachment.ID, &attachment.CreatedAt)
+}
+
+// ListAttachments returns the attachments of a task, oldest first
+func (r *AttachmentRepo) ListAttachments(ctx context.Context, taskID int) ([]*entity.Attachment, error) {
+	query := `
+		SELECT
+			` + attachmentColumns + `
+		FROM attachments
+		WHERE task_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAttachments(rows)
+}
+
+func (r *AttachmentRepo) GetAttachmentByID(ctx context.Context, id int) (*entity.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1`
+	return scanAttachment(r.dao.QueryRow(ctx, query, id))
+}
+
+func (r *AttachmentRepo) DeleteAttachment(ctx context.Context, id int) error {
+	_, err := r.dao.Exec(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	return err
+}
+
+// UsedBytes returns the total size of the files a user uploaded
+func (r *AttachmentRepo) UsedBytes(ctx context.Context, userID string) (int64, error) {
+	var used int64
+	err := r.dao.QueryRow(ctx, `SELECT COALESCE(SUM(size), 0) FROM attachments WHERE user_id = $1`, userID).Scan(&used)
+	return used, err
+}
+
+// ListOrphanedAttachments returns up to limit attachments whose task was purged
+func (r *AttachmentRepo) ListOrphanedAttachments(ctx context.Context, limit int) ([]*entity.Attachment, error) {
+	query := `
+		SELECT
+			` + attachmentColumns + `
+		FROM attachments
+		WHERE task_id IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+
+	rows, err := r.dao.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAttachments(rows)
+}
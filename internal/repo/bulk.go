@@ -0,0 +1,255 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// bulkSavepoint isolates the ops of a best-effort bulk request from each other
+const bulkSavepoint = "bulk_op"
+
+// GetTodosByIDs fetches the live tasks with the given ids, missing ids are left out
+func (r *TaskRepo) GetTodosByIDs(ctx context.Context, ids []int64) ([]*entity.Task, error) {
+	query := `
+		SELECT
+			` + taskColumns + `
+		FROM tasks
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	rows, err := r.dao.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+// ApplyTaskOps runs the ops in order inside one transaction, sending them as a pgx.Batch.
+// An op whose task is gone or no longer at the expected version gets ErrPreconditionFailed.
+// When atomic, the first failing op rolls everything back and its error is returned. Otherwise
+// each op runs under a savepoint so a failing one is skipped and the others still commit.
+// Ops that already carry an error are not run. The Then op of an applied op is run after all
+// of them, a failing one fails the whole call. The revisions of the applied ops are stored
+// in the same transaction, after the writes have locked the rows of their tasks.
+func (r *TaskRepo) ApplyTaskOps(ctx context.Context, userID string, ops []*entity.TaskOp, atomic bool) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var pending []*entity.TaskOp
+	for _, op := range ops {
+		if op.Err == nil {
+			pending = append(pending, op)
+		}
+	}
+
+	for len(pending) > 0 {
+		batch := &pgx.Batch{}
+		for _, op := range pending {
+			if !atomic {
+				batch.Queue("SAVEPOINT " + bulkSavepoint)
+			}
+			queueTaskOp(batch, op)
+			if !atomic {
+				batch.Queue("RELEASE SAVEPOINT " + bulkSavepoint)
+			}
+		}
+
+		results := tx.SendBatch(ctx, batch)
+		failed := -1
+		for i, op := range pending {
+			if !atomic {
+				if _, err := results.Exec(); err != nil {
+					results.Close()
+					return err
+				}
+			}
+
+			err := readTaskOp(results, op)
+			if errors.Is(err, globals.ErrPreconditionFailed) && !atomic {
+				// nothing was written, the statement did not fail
+				op.Err = err
+			} else if err != nil {
+				op.Err = err
+				failed = i
+				break
+			}
+
+			if !atomic {
+				if _, err := results.Exec(); err != nil {
+					results.Close()
+					return err
+				}
+			}
+		}
+		results.Close()
+
+		if failed < 0 {
+			break
+		}
+		if atomic {
+			return pending[failed].Err
+		}
+
+		// the failed statement aborted the transaction, undo just that op and go on with the rest
+		if _, err := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+bulkSavepoint); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, "RELEASE SAVEPOINT "+bulkSavepoint); err != nil {
+			return err
+		}
+		pending = pending[failed+1:]
+	}
+
+	// the follow-up writes only run for the ops that were applied
+	applied := make([]*entity.TaskOp, 0, len(ops))
+	var then []*entity.TaskOp
+	for _, op := range ops {
+		if op.Err != nil {
+			continue
+		}
+		applied = append(applied, op)
+		if op.Then != nil {
+			then = append(then, op.Then)
+		}
+	}
+	if len(then) > 0 {
+		batch := &pgx.Batch{}
+		for _, op := range then {
+			queueTaskOp(batch, op)
+		}
+		results := tx.SendBatch(ctx, batch)
+		for _, op := range then {
+			if err := readTaskOp(results, op); err != nil {
+				results.Close()
+				return err
+			}
+		}
+		if err := results.Close(); err != nil {
+			return err
+		}
+		applied = append(applied, then...)
+	}
+
+	// tags and revisions need the ids of the created tasks, so they go in a last batch
+	followUp := &pgx.Batch{}
+	for _, op := range applied {
+		if op.SetTags && op.Kind != entity.TaskOpDelete && op.Kind != entity.TaskOpRestore {
+			queueTaskTags(followUp, userID, op.Task.ID, op.Tags)
+		}
+		if op.Revision != nil {
+			op.Revision.TaskID = op.Task.ID
+			args, err := revisionArgs(op.Revision)
+			if err != nil {
+				return err
+			}
+			followUp.Queue(insertRevisionQuery, args...)
+		}
+	}
+	if followUp.Len() > 0 {
+		if err := tx.SendBatch(ctx, followUp).Close(); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// queueTaskOp adds the statement of a single op to the batch
+func queueTaskOp(batch *pgx.Batch, op *entity.TaskOp) {
+	task := op.Task
+	switch op.Kind {
+	case entity.TaskOpCreate:
+		batch.Queue(insertTaskQuery, task.UserID, task.ParentID, task.ProjectID, task.Title, task.Description, task.Priority, task.StatusID,
+			nullTime(task.DueAt), task.Recurrence, task.Occurrence)
+	case entity.TaskOpUpdate:
+		batch.Queue(`
+			WITH previous AS (
+				SELECT id, status_id FROM tasks WHERE id = $7 FOR UPDATE
+			), updated AS (
+				UPDATE tasks
+				SET
+					title = $1,
+					description = $2,
+					priority = $3,
+					status_id = $4,
+					due_at = $5,
+					project_id = $6,
+					recurrence = NULLIF($10, ''),
+					version = version + 1,
+					updated_at = now()
+				WHERE id = $7 AND user_id = $8 AND deleted_at IS NULL AND version = $9
+				RETURNING id, status_id, version, updated_at
+			), `+logStatusChange+`
+			SELECT version, updated_at FROM updated
+		`, task.Title, task.Description, task.Priority, task.StatusID, nullTime(task.DueAt), task.ProjectID,
+			task.ID, task.UserID, task.Version, task.Recurrence)
+	case entity.TaskOpDelete:
+		batch.Queue(`
+			WITH RECURSIVE tree AS (
+				SELECT id FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL AND version = $3
+				UNION ALL
+				SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at IS NULL
+			)
+			UPDATE tasks SET deleted_at = now(), version = version + 1 WHERE id IN (SELECT id FROM tree)
+		`, task.ID, task.UserID, task.Version)
+	case entity.TaskOpRestore:
+		// the subtasks deleted along with the task share its deleted_at
+		batch.Queue(`
+			WITH RECURSIVE tree AS (
+				SELECT id, deleted_at FROM tasks
+				WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL AND version = $3
+				UNION ALL
+				SELECT t.id, t.deleted_at FROM tasks t JOIN tree ON t.parent_id = tree.id
+				WHERE t.deleted_at = tree.deleted_at
+			)
+			UPDATE tasks SET deleted_at = NULL, version = version + 1, updated_at = now() WHERE id IN (SELECT id FROM tree)
+		`, task.ID, task.UserID, task.Version)
+	}
+}
+
+// readTaskOp reads the result of an op queued by queueTaskOp back into it
+func readTaskOp(results pgx.BatchResults, op *entity.TaskOp) error {
+	task := op.Task
+	var err error
+	switch op.Kind {
+	case entity.TaskOpCreate:
+		err = results.QueryRow().Scan(&task.ID, &task.Version, &task.CreatedAt, &task.UpdatedAt)
+	case entity.TaskOpUpdate:
+		err = results.QueryRow().Scan(&task.Version, &task.UpdatedAt)
+	case entity.TaskOpDelete, entity.TaskOpRestore:
+		cmdTag, execErr := results.Exec()
+		if execErr == nil && cmdTag.RowsAffected() == 0 {
+			execErr = pgx.ErrNoRows
+		}
+		err = execErr
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return globals.ErrPreconditionFailed
+	}
+	return err
+}
+
+// queueTaskTags adds the statements replacing the tags of a task, creating the user's tags that
+// do not exist yet
+func queueTaskTags(batch *pgx.Batch, userID string, taskID int64, names []string) {
+	batch.Queue(`DELETE FROM task_tags WHERE task_id = $1`, taskID)
+	if len(names) == 0 {
+		return
+	}
+	batch.Queue(`
+		INSERT INTO tags (user_id, name)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (user_id, name) DO NOTHING
+	`, userID, names)
+	batch.Queue(`
+		INSERT INTO task_tags (task_id, tag_id)
+		SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3)
+	`, taskID, userID, names)
+}
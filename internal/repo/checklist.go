@@ -0,0 +1,155 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+const checklistColumns = `id, task_id, text, done, position, created_at, updated_at`
+
+type ChecklistRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewChecklistRepository(dao *pgxpool.Pool) interfaces.ChecklistRepoInterface {
+	return &ChecklistRepo{
+		dao: dao,
+	}
+}
+
+func scanChecklistItem(row pgx.Row) (*entity.ChecklistItem, error) {
+	var (
+		id, taskID           sql.NullInt64
+		text                 sql.NullString
+		done                 sql.NullBool
+		position             sql.NullInt32
+		createdAt, updatedAt sql.NullTime
+	)
+
+	if err := row.Scan(&id, &taskID, &text, &done, &position, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &entity.ChecklistItem{
+		ID:        id.Int64,
+		TaskID:    taskID.Int64,
+		Text:      text.String,
+		Done:      done.Bool,
+		Position:  int(position.Int32),
+		CreatedAt: createdAt.Time,
+		UpdatedAt: updatedAt.Time,
+	}, nil
+}
+
+// CreateItem adds an item at the end of the checklist of its task
+func (r *ChecklistRepo) CreateItem(ctx context.Context, item *entity.ChecklistItem) error {
+	query := `
+		INSERT INTO checklist_items (task_id, text, done, position)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position), 0) + 1 FROM checklist_items WHERE task_id = $1))
+		RETURNING id, position, created_at, updated_at
+	`
+
+	return r.dao.QueryRow(ctx, query, item.TaskID, item.Text, item.Done).
+		Scan(&item.ID, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+}
+
+// ListItems returns the checklist of a task in order
+func (r *ChecklistRepo) ListItems(ctx context.Context, taskID int) ([]*entity.ChecklistItem, error) {
+	query := `
+		SELECT
+			` + checklistColumns + `
+		FROM checklist_items
+		WHERE task_id = $1
+		ORDER BY position ASC, id ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*entity.ChecklistItem
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CountItems returns the checklist progress of each of the given tasks, tasks without a
+// checklist are left out
+func (r *ChecklistRepo) CountItems(ctx context.Context, taskIDs []int64) (map[int64]*entity.ChecklistProgress, error) {
+	query := `
+		SELECT task_id, COUNT(*) FILTER (WHERE done), COUNT(*)
+		FROM checklist_items
+		WHERE task_id = ANY($1)
+		GROUP BY task_id
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make(map[int64]*entity.ChecklistProgress)
+	for rows.Next() {
+		var (
+			taskID      int64
+			done, total int
+		)
+		if err := rows.Scan(&taskID, &done, &total); err != nil {
+			return nil, err
+		}
+		progress[taskID] = &entity.ChecklistProgress{Done: done, Total: total}
+	}
+	return progress, rows.Err()
+}
+
+func (r *ChecklistRepo) GetItemByID(ctx context.Context, id int) (*entity.ChecklistItem, error) {
+	query := `SELECT ` + checklistColumns + ` FROM checklist_items WHERE id = $1`
+	return scanChecklistItem(r.dao.QueryRow(ctx, query, id))
+}
+
+func (r *ChecklistRepo) UpdateItem(ctx context.Context, item *entity.ChecklistItem) error {
+	query := `
+		UPDATE checklist_items
+		SET
+			text = $1,
+			done = $2,
+			updated_at = now()
+		WHERE id = $3
+		RETURNING updated_at
+	`
+
+	return r.dao.QueryRow(ctx, query, item.Text, item.Done, item.ID).Scan(&item.UpdatedAt)
+}
+
+// ReorderItems numbers the items of a task in the given order, ids of other tasks are ignored
+func (r *ChecklistRepo) ReorderItems(ctx context.Context, taskID int, itemIDs []int64) error {
+	query := `
+		UPDATE checklist_items AS c
+		SET
+			position = o.position,
+			updated_at = now()
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+		WHERE c.id = o.id AND c.task_id = $1
+	`
+
+	_, err := r.dao.Exec(ctx, query, taskID, itemIDs)
+	return err
+}
+
+func (r *ChecklistRepo) DeleteItem(ctx context.Context, id int) error {
+	_, err := r.dao.Exec(ctx, `DELETE FROM checklist_items WHERE id = $1`, id)
+	return err
+}
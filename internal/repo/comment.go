@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+const commentColumns = `id, task_id, user_id, body, created_at, updated_at`
+
+type CommentRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewCommentRepository(dao *pgxpool.Pool) interfaces.CommentRepoInterface {
+	return &CommentRepo{
+		dao: dao,
+	}
+}
+
+func scanComment(row pgx.Row) (*entity.Comment, error) {
+	var (
+		id, taskID           sql.NullInt64
+		userID, body         sql.NullString
+		createdAt, updatedAt sql.NullTime
+	)
+
+	if err := row.Scan(&id, &taskID, &userID, &body, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &entity.Comment{
+		ID:        id.Int64,
+		TaskID:    taskID.Int64,
+		UserID:    userID.String,
+		Body:      body.String,
+		CreatedAt: createdAt.Time,
+		UpdatedAt: updatedAt.Time,
+	}, nil
+}
+
+func (r *CommentRepo) CreateComment(ctx context.Context, comment *entity.Comment) error {
+	query := `
+		INSERT INTO comments (task_id, user_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.dao.QueryRow(ctx, query, comment.TaskID, comment.UserID, comment.Body).
+		Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+}
+
+// ListComments returns the comments of a task, oldest first
+func (r *CommentRepo) ListComments(ctx context.Context, taskID, limit, offset int) ([]*entity.Comment, int64, error) {
+	query := `
+		SELECT
+			` + commentColumns + `
+		FROM comments
+		WHERE task_id = $1
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int64
+	if err := r.dao.QueryRow(ctx, `SELECT COUNT(*) FROM comments WHERE task_id = $1`, taskID).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+	return comments, totalCount, nil
+}
+
+// CountComments returns the number of comments of each of the given tasks, tasks without
+// comments are left out
+func (r *CommentRepo) CountComments(ctx context.Context, taskIDs []int64) (map[int64]int64, error) {
+	query := `
+		SELECT task_id, COUNT(*)
+		FROM comments
+		WHERE task_id = ANY($1)
+		GROUP BY task_id
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int64)
+	for rows.Next() {
+		var taskID, count int64
+		if err := rows.Scan(&taskID, &count); err != nil {
+			return nil, err
+		}
+		counts[taskID] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *CommentRepo) GetCommentByID(ctx context.Context, id int) (*entity.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE id = $1`
+	return scanComment(r.dao.QueryRow(ctx, query, id))
+}
+
+func (r *CommentRepo) UpdateComment(ctx context.Context, comment *entity.Comment) error {
+	query := `
+		UPDATE comments
+		SET
+			body = $1,
+			updated_at = now()
+		WHERE id = $2 AND user_id = $3
+		RETURNING updated_at
+	`
+
+	err := r.dao.QueryRow(ctx, query, comment.Body, comment.ID, comment.UserID).Scan(&comment.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("no rows updated — invalid id or user_id mismatch")
+	}
+	return err
+}
+
+func (r *CommentRepo) DeleteComment(ctx context.Context, id int) error {
+	_, err := r.dao.Exec(ctx, `DELETE FROM comments WHERE id = $1`, id)
+	return err
+}
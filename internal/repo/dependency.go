@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+type DependencyRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewDependencyRepository(dao *pgxpool.Pool) interfaces.DependencyRepoInterface {
+	return &DependencyRepo{
+		dao: dao,
+	}
+}
+
+// AddDependency records that taskID is blocked by blockerID. It fails with ErrDependencyCycle
+// when blockerID already waits on taskID, directly or through other tasks. Adds are serialized
+// so two concurrent requests cannot close a cycle between them.
+func (r *DependencyRepo) AddDependency(ctx context.Context, taskID, blockerID int64) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE task_dependencies IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	cycle := `
+		WITH RECURSIVE chain AS (
+			SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocked_by_id FROM task_dependencies d JOIN chain ON d.task_id = chain.blocked_by_id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE blocked_by_id = $2)
+	`
+	var exists bool
+	if err := tx.QueryRow(ctx, cycle, blockerID, taskID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return globals.ErrDependencyCycle
+	}
+
+	insert := `
+		INSERT INTO task_dependencies (task_id, blocked_by_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, insert, taskID, blockerID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RemoveDependency deletes a blocked-by relationship, it reports whether one existed
+func (r *DependencyRepo) RemoveDependency(ctx context.Context, taskID, blockerID int64) (bool, error) {
+	cmdTag, err := r.dao.Exec(ctx, `DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2`, taskID, blockerID)
+	if err != nil {
+		return false, err
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+// ListBlockers returns the live blockers of every given task keyed by the blocked task id
+func (r *DependencyRepo) ListBlockers(ctx context.Context, taskIDs []int64) (map[int64][]*entity.Dependency, error) {
+	query := `
+		SELECT
+			d.task_id, d.blocked_by_id, b.status_id NOT IN ` + openStatuses + `, d.created_at
+		FROM
+			task_dependencies d
+			JOIN tasks b ON b.id = d.blocked_by_id
+		WHERE
+			d.task_id = ANY($1) AND b.deleted_at IS NULL
+		ORDER BY d.created_at ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blockers := make(map[int64][]*entity.Dependency)
+	for rows.Next() {
+		dep := &entity.Dependency{}
+		if err := rows.Scan(&dep.TaskID, &dep.BlockedByID, &dep.BlockerDone, &dep.CreatedAt); err != nil {
+			return nil, err
+		}
+		blockers[dep.TaskID] = append(blockers[dep.TaskID], dep)
+	}
+	return blockers, rows.Err()
+}
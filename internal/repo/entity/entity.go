@@ -7,21 +7,209 @@ import (
 type Task struct {
 	ID          int64
 	UserID      string
+	ParentID    *int64
+	ProjectID   *int64
 	Title       string
 	Description string
 	Priority    int
-	Status      int
+	StatusID    int64
+	Status      string // name of the status, read only
+	Terminal    bool   // whether the status ends the workflow, read only
 	DueAt       time.Time
+	Recurrence  string // RRULE, set on the latest instance of a repeating task
+	Occurrence  int    // 1-based position of the task in its recurrence series
+	Version     int64  // bumped on every change, used for optimistic concurrency
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
+// Dependency is a blocked-by relationship, TaskID cannot be started until BlockedByID is done
+type Dependency struct {
+	TaskID      int64
+	BlockedByID int64
+	BlockerDone bool // the blocker reached a terminal status
+	CreatedAt   time.Time
+}
+
+// Status is a step of a user's workflow. Tasks in a terminal status count as done.
+type Status struct {
+	ID         int64
+	UserID     string
+	Name       string
+	Position   int
+	Color      string
+	IsTerminal bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// StatusChange is one move of a task into a status. FromStatusID is nil for the status the task
+// was created in, both ids are nil once the status has been deleted.
+type StatusChange struct {
+	TaskID       int64
+	FromStatusID *int64
+	ToStatusID   *int64
+	FromStatus   string
+	ToStatus     string
+	Terminal     bool // whether ToStatus ends the workflow
+	ChangedAt    time.Time
+}
+
+// Actions of a Revision
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionRevert  = "revert"
+)
+
+// Revision is one recorded change of a task. Changes maps each changed field to its old and
+// new value and Snapshot holds the tracked fields once the change was made.
+type Revision struct {
+	ID         int64
+	TaskID     int64
+	Revision   int // 1-based, counted per task
+	Action     string
+	ActorID    string
+	RevertedTo *int // the revision a revert went back to
+	Changes    map[string]FieldChange
+	Snapshot   *TaskSnapshot
+	CreatedAt  time.Time
+}
+
+// FieldChange is the value of a field before and after a change, stored as JSON
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// TaskSnapshot is the state of a task kept with each revision, stored as JSON
+type TaskSnapshot struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Priority    string     `json:"priority"`
+	StatusID    int64      `json:"status_id"`
+	Status      string     `json:"status"`
+	DueAt       *time.Time `json:"dueAt"`
+	ProjectID   *int64     `json:"project_id"`
+	Recurrence  string     `json:"recurrence"`
+	Tags        []string   `json:"tags"`
+	Deleted     bool       `json:"deleted"`
+}
+
+// Kinds of TaskOp
+const (
+	TaskOpCreate  = "create"
+	TaskOpUpdate  = "update"
+	TaskOpDelete  = "delete"
+	TaskOpRestore = "restore"
+)
+
+// TaskOp is one write of a task, a bulk request is a list of them. Task holds the full row to write and is filled with
+// the stored id, version and timestamps once applied, its Version is the one expected in the
+// database. Err is set when the write was not applied.
+type TaskOp struct {
+	Kind     string
+	Task     *Task
+	Tags     []string
+	SetTags  bool
+	Revision *Revision // recorded along with the write, its TaskID is set once applied
+	Then     *TaskOp   // written once the op applied, in the same transaction
+	Err      error
+}
+
+// TaskFilter holds the criteria used when listing a user's tasks
+type TaskFilter struct {
+	StatusID    int64 // 0 lists every status
+	Priorities  []int
+	ParentID    *int64
+	ProjectID   *int64
+	TagsAny     []string
+	TagsAll     []string
+	RootsOnly   bool
+	Actionable  bool // pending tasks without pending blockers
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	Overdue     bool
+	NoDueDate   bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Sort        []SortKey
+	Cursor      *Cursor // keyset pagination, Offset is ignored when set
+	CountTotal  bool
+	Limit       int
+	Offset      int
+}
+
+// Cursor holds the sort values of the row a keyset page starts after (or before when Backward)
+type Cursor struct {
+	ID        int64
+	DueAt     *time.Time
+	Priority  int
+	Title     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Backward  bool
+}
+
+// SortKey is one column of a multi-column sort
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
 // User struct represents the user data
 type User struct {
-	ID       string
-	UserName string
-	Email    string
-	Password string
+	ID              string
+	UserName        string
+	Email           string
+	Password        string
+	EmailVerifiedAt *time.Time
+	TOTPSecret      string     // set on enrollment, only used once TOTPEnabledAt is set
+	TOTPEnabledAt   *time.Time // two-factor authentication is on
+	TOTPLastStep    int64      // time step of the last accepted code
+}
+
+// RefreshToken is a stored refresh token of a login. Tokens of the same login share FamilyID,
+// only the latest of a family is neither used nor revoked.
+type RefreshToken struct {
+	ID              int64
+	UserID          string
+	FamilyID        string
+	TokenHash       string
+	AccessJTI       string // id of the access token issued with this refresh token
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	UsedAt          *time.Time
+	RevokedAt       *time.Time
+}
+
+// Session is a login of a user on a device, its id is the family of its refresh tokens
+type Session struct {
+	ID         string
+	UserID     string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// LoginAttempt is a successful or failed login, UserID is empty when the email matched no user
+type LoginAttempt struct {
+	ID        int64
+	UserID    string
+	Email     string
+	Success   bool
+	Reason    string
+	UserAgent string
+	IP        string
+	CreatedAt time.Time
 }
 
 // Login struct represents the user login data
@@ -29,3 +217,70 @@ type Login struct {
 	Email    string
 	Password string
 }
+
+// Project groups a user's tasks into a list
+type Project struct {
+	ID        int64
+	UserID    string
+	Name      string
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Tag is a user defined label that can be attached to many tasks
+type Tag struct {
+	ID         int64
+	UserID     string
+	Name       string
+	UsageCount int64
+	CreatedAt  time.Time
+}
+
+// Comment is a note left on a task by a user with access to it
+type Comment struct {
+	ID        int64
+	TaskID    int64
+	UserID    string
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Attachment is a file uploaded to a task, the bytes are kept in storage under StorageKey. A
+// nil TaskID means the task was purged and the bytes still have to be removed.
+type Attachment struct {
+	ID          int64
+	TaskID      *int64
+	UserID      string
+	Filename    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	CreatedAt   time.Time
+}
+
+// ChecklistItem is a step of a task that is ticked off without being a task of its own
+type ChecklistItem struct {
+	ID        int64
+	TaskID    int64
+	Text      string
+	Done      bool
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ChecklistProgress counts the checklist items of a task and how many of them are done
+type ChecklistProgress struct {
+	Done  int
+	Total int
+}
+
+// SearchHit is a task matched by a full-text search with its rank and highlighted snippets
+type SearchHit struct {
+	Task               *Task
+	Rank               float64
+	TitleSnippet       string
+	DescriptionSnippet string
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
 )
@@ -10,12 +11,117 @@ type UserRepoInterface interface {
 	CreateUser(ctx context.Context, user *entity.User) error
 	GetUserByID(ctx context.Context, ID string) (*entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	UpdatePassword(ctx context.Context, userID, password string) error
+	SetTOTPSecret(ctx context.Context, userID, secret string) error
+	EnableTOTP(ctx context.Context, userID string, step int64, codeHashes []string) error
+	DisableTOTP(ctx context.Context, userID string) error
+	AcceptTOTPStep(ctx context.Context, userID string, step int64) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
+}
+
+type TokenRepoInterface interface {
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error)
+	GetRefreshTokenByAccessJTI(ctx context.Context, jti string) (*entity.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, usedID int64, next *entity.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) ([]*entity.RefreshToken, error)
+	RevokeUserTokens(ctx context.Context, userID string) ([]*entity.RefreshToken, error)
+	DeleteExpiredRefreshTokens(ctx context.Context, userID string) error
+	CreatePasswordReset(ctx context.Context, userID, hash string, expiresAt time.Time) error
+	ConsumePasswordReset(ctx context.Context, hash string) (string, error)
+	CreateEmailVerification(ctx context.Context, userID, hash string, expiresAt time.Time) error
+	VerifyEmail(ctx context.Context, hash string) (string, error)
+}
+
+type SessionRepoInterface interface {
+	CreateSession(ctx context.Context, session *entity.Session) error
+	TouchSession(ctx context.Context, session *entity.Session) error
+	ListSessions(ctx context.Context, userID string) ([]*entity.Session, error)
+	GetSessionByID(ctx context.Context, id string) (*entity.Session, error)
+	RevokeSession(ctx context.Context, id string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+	CreateLoginAttempt(ctx context.Context, attempt *entity.LoginAttempt) error
+	ListLoginAttempts(ctx context.Context, userID string, limit, offset int) ([]*entity.LoginAttempt, int64, error)
 }
 
 type TaskRepoInterface interface {
-	CreateTodo(ctx context.Context, task *entity.Task) error
-	ListAllTodos(ctx context.Context, userID string, statusFilter string, limit, offset int) ([]*entity.Task, int64, error)
-	UpdateTodoByID(ctx context.Context, id int, updatedTask *entity.Task) error
-	DeleteTodo(ctx context.Context, id int) error
+	ListAllTodos(ctx context.Context, userID string, filter *entity.TaskFilter) ([]*entity.Task, int64, error)
+	SearchTodos(ctx context.Context, userID, query string, statusID int64, limit, offset int) ([]*entity.SearchHit, int64, error)
+	ListSubtasks(ctx context.Context, userID string, parentIDs []int64) ([]*entity.Task, error)
+	CountOpenSubtasks(ctx context.Context, id int) (int64, error)
+	ListStatusChanges(ctx context.Context, id int) ([]*entity.StatusChange, error)
 	GetTodoByID(ctx context.Context, id int) (*entity.Task, error)
+	GetTodosByIDs(ctx context.Context, ids []int64) ([]*entity.Task, error)
+	ApplyTaskOps(ctx context.Context, userID string, ops []*entity.TaskOp, atomic bool) error
+	GetTrashedTodoByID(ctx context.Context, id int) (*entity.Task, error)
+	ListTrash(ctx context.Context, userID string, limit, offset int) ([]*entity.Task, int64, error)
+	PurgeTodo(ctx context.Context, id int) error
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+}
+
+type DependencyRepoInterface interface {
+	AddDependency(ctx context.Context, taskID, blockerID int64) error
+	RemoveDependency(ctx context.Context, taskID, blockerID int64) (bool, error)
+	ListBlockers(ctx context.Context, taskIDs []int64) (map[int64][]*entity.Dependency, error)
+}
+
+type StatusRepoInterface interface {
+	ListStatuses(ctx context.Context, userID string) ([]*entity.Status, error)
+	CreateDefaultStatuses(ctx context.Context, userID string) error
+	CreateStatus(ctx context.Context, status *entity.Status) error
+	GetStatusByID(ctx context.Context, id int) (*entity.Status, error)
+	UpdateStatus(ctx context.Context, status *entity.Status) error
+	DeleteStatus(ctx context.Context, id int, moveTo int64) error
+	ListTransitions(ctx context.Context, userID string) (map[int64][]int64, error)
+	SetTransitions(ctx context.Context, fromID int64, toIDs []int64) error
+}
+
+type RevisionRepoInterface interface {
+	ListRevisions(ctx context.Context, taskID, limit, offset int) ([]*entity.Revision, int64, error)
+	GetRevision(ctx context.Context, taskID, revision int) (*entity.Revision, error)
+}
+
+type CommentRepoInterface interface {
+	CreateComment(ctx context.Context, comment *entity.Comment) error
+	ListComments(ctx context.Context, taskID, limit, offset int) ([]*entity.Comment, int64, error)
+	CountComments(ctx context.Context, taskIDs []int64) (map[int64]int64, error)
+	GetCommentByID(ctx context.Context, id int) (*entity.Comment, error)
+	UpdateComment(ctx context.Context, comment *entity.Comment) error
+	DeleteComment(ctx context.Context, id int) error
+}
+
+type AttachmentRepoInterface interface {
+	CreateAttachment(ctx context.Context, attachment *entity.Attachment, quota int64) error
+	ListAttachments(ctx context.Context, taskID int) ([]*entity.Attachment, error)
+	GetAttachmentByID(ctx context.Context, id int) (*entity.Attachment, error)
+	DeleteAttachment(ctx context.Context, id int) error
+	UsedBytes(ctx context.Context, userID string) (int64, error)
+	ListOrphanedAttachments(ctx context.Context, limit int) ([]*entity.Attachment, error)
+}
+
+type ChecklistRepoInterface interface {
+	CreateItem(ctx context.Context, item *entity.ChecklistItem) error
+	ListItems(ctx context.Context, taskID int) ([]*entity.ChecklistItem, error)
+	CountItems(ctx context.Context, taskIDs []int64) (map[int64]*entity.ChecklistProgress, error)
+	GetItemByID(ctx context.Context, id int) (*entity.ChecklistItem, error)
+	UpdateItem(ctx context.Context, item *entity.ChecklistItem) error
+	ReorderItems(ctx context.Context, taskID int, itemIDs []int64) error
+	DeleteItem(ctx context.Context, id int) error
+}
+
+type ProjectRepoInterface interface {
+	CreateProject(ctx context.Context, project *entity.Project) error
+	ListProjects(ctx context.Context, userID string) ([]*entity.Project, error)
+	GetProjectByID(ctx context.Context, id int) (*entity.Project, error)
+	GetDefaultProject(ctx context.Context, userID string) (*entity.Project, error)
+	UpdateProject(ctx context.Context, project *entity.Project) error
+	DeleteProject(ctx context.Context, id int, moveTo int64, trashTasks bool) error
+}
+
+type TagRepoInterface interface {
+	ListTagsForTasks(ctx context.Context, taskIDs []int64) (map[int64][]string, error)
+	ListTags(ctx context.Context, userID string) ([]*entity.Tag, error)
+	GetTagByID(ctx context.Context, id int) (*entity.Tag, error)
+	RenameTag(ctx context.Context, id int, name string) error
+	MergeTags(ctx context.Context, sourceID, targetID int) error
 }
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+const projectColumns = `id, user_id, name, is_default, created_at, updated_at`
+
+type ProjectRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewProjectRepository(dao *pgxpool.Pool) interfaces.ProjectRepoInterface {
+	return &ProjectRepo{
+		dao: dao,
+	}
+}
+
+func scanProject(row pgx.Row) (*entity.Project, error) {
+	var (
+		id                   sql.NullInt64
+		userID, name         sql.NullString
+		isDefault            sql.NullBool
+		createdAt, updatedAt sql.NullTime
+	)
+
+	if err := row.Scan(&id, &userID, &name, &isDefault, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &entity.Project{
+		ID:        id.Int64,
+		UserID:    userID.String,
+		Name:      name.String,
+		IsDefault: isDefault.Bool,
+		CreatedAt: createdAt.Time,
+		UpdatedAt: updatedAt.Time,
+	}, nil
+}
+
+func (r *ProjectRepo) CreateProject(ctx context.Context, project *entity.Project) error {
+	query := `
+		INSERT INTO projects (user_id, name, is_default)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.dao.QueryRow(ctx, query, project.UserID, project.Name, project.IsDefault).
+		Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt)
+}
+
+func (r *ProjectRepo) ListProjects(ctx context.Context, userID string) ([]*entity.Project, error) {
+	query := `
+		SELECT
+			` + projectColumns + `
+		FROM
+			projects
+		WHERE
+			user_id = $1
+		ORDER BY is_default DESC, name ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*entity.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	return projects, rows.Err()
+}
+
+func (r *ProjectRepo) GetProjectByID(ctx context.Context, id int) (*entity.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE id = $1`
+	return scanProject(r.dao.QueryRow(ctx, query, id))
+}
+
+func (r *ProjectRepo) GetDefaultProject(ctx context.Context, userID string) (*entity.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE user_id = $1 AND is_default`
+	return scanProject(r.dao.QueryRow(ctx, query, userID))
+}
+
+func (r *ProjectRepo) UpdateProject(ctx context.Context, project *entity.Project) error {
+	query := `
+		UPDATE projects
+		SET
+			name = $1,
+			updated_at = now()
+		WHERE id = $2 AND user_id = $3
+		RETURNING updated_at
+	`
+
+	err := r.dao.QueryRow(ctx, query, project.Name, project.ID, project.UserID).Scan(&project.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("no rows updated — invalid id or user_id mismatch")
+	}
+	return err
+}
+
+// DeleteProject removes a project in a single transaction. Its tasks, including the ones already
+// in the trash, are moved to moveTo. With trashTasks the live ones are first moved to the trash
+// together with their subtasks, the same way TaskRepo.DeleteTodo does, so they stay restorable
+// until the trash is purged.
+func (r *ProjectRepo) DeleteProject(ctx context.Context, id int, moveTo int64, trashTasks bool) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if trashTasks {
+		// now() is the same for the whole transaction, so each tree shares one deleted_at
+		if _, err := tx.Exec(ctx, `
+			WITH RECURSIVE tree AS (
+				SELECT id FROM tasks WHERE project_id = $1 AND deleted_at IS NULL
+				UNION
+				SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at IS NULL
+			)
+			UPDATE tasks SET deleted_at = now(), version = version + 1 WHERE id IN (SELECT id FROM tree)
+		`, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE tasks SET project_id = $1, version = version + 1, updated_at = now() WHERE project_id = $2`, moveTo, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM projects WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// whereBuilder collects AND-ed conditions together with their positional arguments
+type whereBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+// add appends a condition, each "?" in it is bound to the next of args as $n
+func (w *whereBuilder) add(cond string, args ...interface{}) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(w.args)), 1)
+	}
+	w.conds = append(w.conds, cond)
+}
+
+// next returns the placeholder of the next argument that will be bound
+func (w *whereBuilder) next() string {
+	return fmt.Sprintf("$%d", len(w.args)+1)
+}
+
+func (w *whereBuilder) String() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// nullTime stores a zero time as NULL instead of 0001-01-01
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+const revisionColumns = `id, task_id, revision, action, actor_id, reverted_to, changes, snapshot, created_at`
+
+// insertRevisionQuery stores a revision as the next one of its task, the changes and snapshot
+// are passed as JSON text. It must run in the transaction that wrote the task, whose row lock
+// keeps a concurrent write of the same task from taking the same number.
+const insertRevisionQuery = `
+	INSERT INTO task_revisions (task_id, revision, action, actor_id, reverted_to, changes, snapshot)
+	VALUES ($1, (SELECT COALESCE(MAX(revision), 0) + 1 FROM task_revisions WHERE task_id = $1), $2, $3, $4, $5::jsonb, $6::jsonb)
+	RETURNING id, revision, created_at
+`
+
+type RevisionRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewRevisionRepository(dao *pgxpool.Pool) interfaces.RevisionRepoInterface {
+	return &RevisionRepo{
+		dao: dao,
+	}
+}
+
+func scanRevision(row pgx.Row) (*entity.Revision, error) {
+	var (
+		id, taskID         sql.NullInt64
+		revision, reverted sql.NullInt32
+		action, actorID    sql.NullString
+		changes, snapshot  []byte
+		createdAt          sql.NullTime
+	)
+
+	if err := row.Scan(&id, &taskID, &revision, &action, &actorID, &reverted, &changes, &snapshot, &createdAt); err != nil {
+		return nil, err
+	}
+
+	rev := &entity.Revision{
+		ID:        id.Int64,
+		TaskID:    taskID.Int64,
+		Revision:  int(revision.Int32),
+		Action:    action.String,
+		ActorID:   actorID.String,
+		CreatedAt: createdAt.Time,
+	}
+	if reverted.Valid {
+		revertedTo := int(reverted.Int32)
+		rev.RevertedTo = &revertedTo
+	}
+	if err := json.Unmarshal(changes, &rev.Changes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &rev.Snapshot); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// revisionArgs returns the arguments of insertRevisionQuery for a revision
+func revisionArgs(rev *entity.Revision) ([]interface{}, error) {
+	changes, err := json.Marshal(rev.Changes)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := json.Marshal(rev.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{rev.TaskID, rev.Action, rev.ActorID, rev.RevertedTo, string(changes), string(snapshot)}, nil
+}
+
+// ListRevisions returns the revisions of a task, newest first
+func (r *RevisionRepo) ListRevisions(ctx context.Context, taskID, limit, offset int) ([]*entity.Revision, int64, error) {
+	query := `
+		SELECT
+			` + revisionColumns + `
+		FROM task_revisions
+		WHERE task_id = $1
+		ORDER BY revision DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var revisions []*entity.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int64
+	if err := r.dao.QueryRow(ctx, `SELECT COUNT(*) FROM task_revisions WHERE task_id = $1`, taskID).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+	return revisions, totalCount, nil
+}
+
+func (r *RevisionRepo) GetRevision(ctx context.Context, taskID, revision int) (*entity.Revision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM task_revisions WHERE task_id = $1 AND revision = $2`
+	return scanRevision(r.dao.QueryRow(ctx, query, taskID, revision))
+}
@@ -0,0 +1,178 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+const sessionColumns = `id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, revoked_at`
+
+const loginAttemptColumns = `id, user_id, email, success, reason, user_agent, ip, created_at`
+
+type SessionRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewSessionRepository(dao *pgxpool.Pool) interfaces.SessionRepoInterface {
+	return &SessionRepo{
+		dao: dao,
+	}
+}
+
+func scanSession(row pgx.Row) (*entity.Session, error) {
+	var (
+		id, userID, userAgent, ip                 sql.NullString
+		createdAt, lastSeenAt, expiresAt, revoked sql.NullTime
+	)
+
+	if err := row.Scan(&id, &userID, &userAgent, &ip, &createdAt, &lastSeenAt, &expiresAt, &revoked); err != nil {
+		return nil, err
+	}
+
+	return &entity.Session{
+		ID:         id.String,
+		UserID:     userID.String,
+		UserAgent:  userAgent.String,
+		IP:         ip.String,
+		CreatedAt:  createdAt.Time,
+		LastSeenAt: lastSeenAt.Time,
+		ExpiresAt:  expiresAt.Time,
+		RevokedAt:  nullTime(revoked.Time),
+	}, nil
+}
+
+func (r *SessionRepo) CreateSession(ctx context.Context, session *entity.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, last_seen_at
+	`
+
+	return r.dao.QueryRow(ctx, query, session.ID, session.UserID, session.UserAgent, session.IP, session.ExpiresAt).
+		Scan(&session.CreatedAt, &session.LastSeenAt)
+}
+
+// TouchSession records that a session was used again from the given device
+func (r *SessionRepo) TouchSession(ctx context.Context, session *entity.Session) error {
+	query := `
+		UPDATE sessions
+		SET
+			user_agent = $1,
+			ip = $2,
+			expires_at = $3,
+			last_seen_at = now()
+		WHERE id = $4
+	`
+
+	_, err := r.dao.Exec(ctx, query, session.UserAgent, session.IP, session.ExpiresAt, session.ID)
+	return err
+}
+
+// ListSessions returns the sessions of a user that are neither revoked nor expired, most
+// recently used first
+func (r *SessionRepo) ListSessions(ctx context.Context, userID string) ([]*entity.Session, error) {
+	query := `
+		SELECT
+			` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.dao.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*entity.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *SessionRepo) GetSessionByID(ctx context.Context, id string) (*entity.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+	return scanSession(r.dao.QueryRow(ctx, query, id))
+}
+
+func (r *SessionRepo) RevokeSession(ctx context.Context, id string) error {
+	_, err := r.dao.Exec(ctx, `UPDATE sessions SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`, id)
+	return err
+}
+
+func (r *SessionRepo) RevokeUserSessions(ctx context.Context, userID string) error {
+	_, err := r.dao.Exec(ctx, `UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+func (r *SessionRepo) CreateLoginAttempt(ctx context.Context, attempt *entity.LoginAttempt) error {
+	query := `
+		INSERT INTO login_attempts (user_id, email, success, reason, user_agent, ip)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	return r.dao.QueryRow(ctx, query, attempt.UserID, attempt.Email, attempt.Success, attempt.Reason,
+		attempt.UserAgent, attempt.IP).Scan(&attempt.ID, &attempt.CreatedAt)
+}
+
+// ListLoginAttempts returns the login attempts on the account of a user, newest first
+func (r *SessionRepo) ListLoginAttempts(ctx context.Context, userID string, limit, offset int) ([]*entity.LoginAttempt, int64, error) {
+	query := `
+		SELECT
+			` + loginAttemptColumns + `
+		FROM login_attempts
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.dao.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var attempts []*entity.LoginAttempt
+	for rows.Next() {
+		var (
+			id                                sql.NullInt64
+			uid, email, reason, userAgent, ip sql.NullString
+			success                           sql.NullBool
+			createdAt                         sql.NullTime
+		)
+		if err := rows.Scan(&id, &uid, &email, &success, &reason, &userAgent, &ip, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		attempts = append(attempts, &entity.LoginAttempt{
+			ID:        id.Int64,
+			UserID:    uid.String,
+			Email:     email.String,
+			Success:   success.Bool,
+			Reason:    reason.String,
+			UserAgent: userAgent.String,
+			IP:        ip.String,
+			CreatedAt: createdAt.Time,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int64
+	if err := r.dao.QueryRow(ctx, `SELECT COUNT(*) FROM login_attempts WHERE user_id = $1`, userID).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+	return attempts, totalCount, nil
+}
@@ -0,0 +1,212 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+const statusColumns = `id, user_id, name, position, color, is_terminal, created_at, updated_at`
+
+// openStatuses selects the ids of the statuses that do not end a workflow
+const openStatuses = `(SELECT id FROM statuses WHERE NOT is_terminal)`
+
+type StatusRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewStatusRepository(dao *pgxpool.Pool) interfaces.StatusRepoInterface {
+	return &StatusRepo{
+		dao: dao,
+	}
+}
+
+func scanStatus(row pgx.Row) (*entity.Status, error) {
+	var (
+		id, position         sql.NullInt64
+		userID, name, color  sql.NullString
+		isTerminal           sql.NullBool
+		createdAt, updatedAt sql.NullTime
+	)
+
+	if err := row.Scan(&id, &userID, &name, &position, &color, &isTerminal, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &entity.Status{
+		ID:         id.Int64,
+		UserID:     userID.String,
+		Name:       name.String,
+		Position:   int(position.Int64),
+		Color:      color.String,
+		IsTerminal: isTerminal.Bool,
+		CreatedAt:  createdAt.Time,
+		UpdatedAt:  updatedAt.Time,
+	}, nil
+}
+
+// ListStatuses returns the workflow of a user in order
+func (r *StatusRepo) ListStatuses(ctx context.Context, userID string) ([]*entity.Status, error) {
+	query := `
+		SELECT
+			` + statusColumns + `
+		FROM
+			statuses
+		WHERE
+			user_id = $1
+		ORDER BY position ASC, id ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statuses []*entity.Status
+	for rows.Next() {
+		status, err := scanStatus(rows)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+// CreateDefaultStatuses seeds the PENDING and COMPLETED statuses for a user, existing ones are kept
+func (r *StatusRepo) CreateDefaultStatuses(ctx context.Context, userID string) error {
+	query := `
+		INSERT INTO statuses (user_id, name, position, color, is_terminal)
+		VALUES ($1, $2, 1, $3, false), ($1, $4, 2, $5, true)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.dao.Exec(ctx, query, userID,
+		globals.PENDING, globals.DefaultStatusColors[globals.PENDING],
+		globals.COMPLETED, globals.DefaultStatusColors[globals.COMPLETED])
+	return err
+}
+
+func (r *StatusRepo) CreateStatus(ctx context.Context, status *entity.Status) error {
+	query := `
+		INSERT INTO statuses (user_id, name, position, color, is_terminal)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.dao.QueryRow(ctx, query, status.UserID, status.Name, status.Position, status.Color, status.IsTerminal).
+		Scan(&status.ID, &status.CreatedAt, &status.UpdatedAt)
+}
+
+func (r *StatusRepo) GetStatusByID(ctx context.Context, id int) (*entity.Status, error) {
+	query := `SELECT ` + statusColumns + ` FROM statuses WHERE id = $1`
+	return scanStatus(r.dao.QueryRow(ctx, query, id))
+}
+
+func (r *StatusRepo) UpdateStatus(ctx context.Context, status *entity.Status) error {
+	query := `
+		UPDATE statuses
+		SET
+			name = $1,
+			position = $2,
+			color = $3,
+			is_terminal = $4,
+			updated_at = now()
+		WHERE id = $5 AND user_id = $6
+		RETURNING updated_at
+	`
+
+	err := r.dao.QueryRow(ctx, query, status.Name, status.Position, status.Color, status.IsTerminal, status.ID, status.UserID).
+		Scan(&status.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("no rows updated — invalid id or user_id mismatch")
+	}
+	return err
+}
+
+// DeleteStatus removes a status in a single transaction, its tasks (trashed ones included) move to
+// moveTo and the move is recorded in their status history
+func (r *StatusRepo) DeleteStatus(ctx context.Context, id int, moveTo int64) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	move := `
+		WITH previous AS (
+			SELECT id, status_id FROM tasks WHERE status_id = $2 FOR UPDATE
+		), updated AS (
+			UPDATE tasks SET status_id = $1, version = version + 1, updated_at = now() WHERE status_id = $2
+			RETURNING id, status_id, updated_at
+		), ` + logStatusChange + `
+		SELECT COUNT(*) FROM updated
+	`
+	if _, err := tx.Exec(ctx, move, moveTo, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM statuses WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListTransitions returns the allowed next statuses of every status of a user that restricts them
+func (r *StatusRepo) ListTransitions(ctx context.Context, userID string) (map[int64][]int64, error) {
+	query := `
+		SELECT st.from_status_id, st.to_status_id
+		FROM status_transitions st
+		JOIN statuses s ON s.id = st.from_status_id
+		WHERE s.user_id = $1
+	`
+
+	rows, err := r.dao.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := make(map[int64][]int64)
+	for rows.Next() {
+		var from, to int64
+		if err := rows.Scan(&from, &to); err != nil {
+			return nil, err
+		}
+		transitions[from] = append(transitions[from], to)
+	}
+	return transitions, rows.Err()
+}
+
+// SetTransitions replaces the allowed next statuses of a status, an empty list lifts the restriction
+func (r *StatusRepo) SetTransitions(ctx context.Context, fromID int64, toIDs []int64) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM status_transitions WHERE from_status_id = $1`, fromID); err != nil {
+		return err
+	}
+
+	if len(toIDs) > 0 {
+		insert := `
+			INSERT INTO status_transitions (from_status_id, to_status_id)
+			SELECT $1, unnest($2::int[])
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := tx.Exec(ctx, insert, fromID, toIDs); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,151 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+type TagRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewTagRepository(dao *pgxpool.Pool) interfaces.TagRepoInterface {
+	return &TagRepo{
+		dao: dao,
+	}
+}
+
+// ListTagsForTasks returns the tag names of every given task keyed by task id
+func (r *TagRepo) ListTagsForTasks(ctx context.Context, taskIDs []int64) (map[int64][]string, error) {
+	query := `
+		SELECT
+			tt.task_id, g.name
+		FROM
+			task_tags tt
+			JOIN tags g ON g.id = tt.tag_id
+		WHERE
+			tt.task_id = ANY($1)
+		ORDER BY g.name ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]string)
+	for rows.Next() {
+		var (
+			taskID int64
+			name   string
+		)
+		if err := rows.Scan(&taskID, &name); err != nil {
+			return nil, err
+		}
+		tags[taskID] = append(tags[taskID], name)
+	}
+	return tags, rows.Err()
+}
+
+// ListTags returns all tags of a user along with the number of tasks using each one
+func (r *TagRepo) ListTags(ctx context.Context, userID string) ([]*entity.Tag, error) {
+	query := `
+		SELECT
+			g.id, g.user_id, g.name, g.created_at, COUNT(tt.task_id)
+		FROM
+			tags g
+			LEFT JOIN task_tags tt ON tt.tag_id = g.id
+				AND tt.task_id IN (SELECT id FROM tasks WHERE deleted_at IS NULL)
+		WHERE
+			g.user_id = $1
+		GROUP BY g.id
+		ORDER BY g.name ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*entity.Tag
+	for rows.Next() {
+		var (
+			id, usage   sql.NullInt64
+			owner, name sql.NullString
+			createdAt   sql.NullTime
+		)
+		if err := rows.Scan(&id, &owner, &name, &createdAt, &usage); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &entity.Tag{
+			ID:         id.Int64,
+			UserID:     owner.String,
+			Name:       name.String,
+			UsageCount: usage.Int64,
+			CreatedAt:  createdAt.Time,
+		})
+	}
+	return tags, rows.Err()
+}
+
+func (r *TagRepo) GetTagByID(ctx context.Context, id int) (*entity.Tag, error) {
+	query := `SELECT id, user_id, name, created_at FROM tags WHERE id = $1`
+
+	var (
+		tagID       sql.NullInt64
+		owner, name sql.NullString
+		createdAt   sql.NullTime
+	)
+	if err := r.dao.QueryRow(ctx, query, id).Scan(&tagID, &owner, &name, &createdAt); err != nil {
+		return nil, err
+	}
+
+	return &entity.Tag{
+		ID:        tagID.Int64,
+		UserID:    owner.String,
+		Name:      name.String,
+		CreatedAt: createdAt.Time,
+	}, nil
+}
+
+func (r *TagRepo) RenameTag(ctx context.Context, id int, name string) error {
+	_, err := r.dao.Exec(ctx, `UPDATE tags SET name = $1 WHERE id = $2`, name, id)
+	return err
+}
+
+// MergeTags moves every task of the source tag onto the target tag and drops the source tag
+func (r *TagRepo) MergeTags(ctx context.Context, sourceID, targetID int) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	move := `
+		INSERT INTO task_tags (task_id, tag_id)
+		SELECT task_id, $1 FROM task_tags WHERE tag_id = $2
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, move, targetID, sourceID); err != nil {
+		return err
+	}
+
+	// the tag list is part of a task, so its ETag has to change
+	bump := `UPDATE tasks SET version = version + 1, updated_at = now() WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = $1)`
+	if _, err := tx.Exec(ctx, bump, sourceID); err != nil {
+		return err
+	}
+
+	// task_tags rows of the source are removed by the foreign key cascade
+	if _, err := tx.Exec(ctx, `DELETE FROM tags WHERE id = $1`, sourceID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -5,13 +5,45 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
 	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
-	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
 )
 
+// taskColumns is the column list read by scanTask, keep both in the same order. The name and
+// terminal flag of the status are looked up so callers never need the statuses table.
+const taskColumns = `id, user_id, parent_id, project_id, title, description, priority, status_id,
+	(SELECT name FROM statuses WHERE statuses.id = status_id), (SELECT is_terminal FROM statuses WHERE statuses.id = status_id),
+	due_at, recurrence, occurrence, version, created_at, updated_at`
+
+// insertTaskQuery creates a task and records the status it starts in, it returns the id,
+// version and timestamps of the new task
+const insertTaskQuery = `
+	WITH created AS (
+		INSERT INTO tasks (user_id, parent_id, project_id, title, description, priority, status_id, due_at, recurrence, occurrence)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), GREATEST($10, 1))
+		RETURNING id, status_id, version, created_at, updated_at
+	), logged AS (
+		INSERT INTO task_status_history (task_id, to_status_id, changed_at)
+		SELECT id, status_id, created_at FROM created
+	)
+	SELECT id, version, created_at, updated_at FROM created
+`
+
+// logStatusChange is appended to a WITH clause holding a "previous" row set (id, status_id) read
+// before an "updated" one (id, status_id, updated_at) and records the tasks whose status changed
+const logStatusChange = `
+	logged AS (
+		INSERT INTO task_status_history (task_id, from_status_id, to_status_id, changed_at)
+		SELECT u.id, p.status_id, u.status_id, u.updated_at
+		FROM updated u JOIN previous p ON p.id = u.id
+		WHERE p.status_id <> u.status_id
+	)
+`
+
 type TaskRepo struct {
 	dao *pgxpool.Pool
 }
@@ -22,206 +54,551 @@ func NewTaskRepository(dao *pgxpool.Pool) interfaces.TaskRepoInterface {
 	}
 }
 
-func (r *TaskRepo) CreateTodo(ctx context.Context, task *entity.Task) error {
-	query := `
-		INSERT INTO tasks (user_id, title, description, priority, status, due_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at, updated_at
-	`
+// scanTask reads a single row selected with taskColumns into a task entity
+func scanTask(row pgx.Row) (*entity.Task, error) {
+	var (
+		id, parentID, projectID, version, statusID   sql.NullInt64
+		priority, occurrence                         sql.NullInt32
+		userID, title, description, recurrence, name sql.NullString
+		terminal                                     sql.NullBool
+		dueAt, createdAt, updatedAt                  sql.NullTime
+	)
+
+	if err := row.Scan(
+		&id,
+		&userID,
+		&parentID,
+		&projectID,
+		&title,
+		&description,
+		&priority,
+		&statusID,
+		&name,
+		&terminal,
+		&dueAt,
+		&recurrence,
+		&occurrence,
+		&version,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	task := &entity.Task{
+		ID:          id.Int64,
+		UserID:      userID.String,
+		Title:       title.String,
+		Description: description.String,
+		Priority:    int(priority.Int32),
+		StatusID:    statusID.Int64,
+		Status:      name.String,
+		Terminal:    terminal.Bool,
+		Recurrence:  recurrence.String,
+		Occurrence:  int(occurrence.Int32),
+		Version:     version.Int64,
+		CreatedAt:   createdAt.Time,
+		UpdatedAt:   updatedAt.Time,
+	}
+	if parentID.Valid {
+		task.ParentID = &parentID.Int64
+	}
+	if projectID.Valid {
+		task.ProjectID = &projectID.Int64
+	}
+	if dueAt.Valid {
+		task.DueAt = dueAt.Time
+	}
+	return task, nil
+}
+
+// scanTasks drains rows selected with taskColumns
+func scanTasks(rows pgx.Rows) ([]*entity.Task, error) {
+	defer rows.Close()
+
+	var tasks []*entity.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
 
-	err := r.dao.QueryRow(
-		ctx,
-		query,
-		task.UserID,
-		task.Title,
-		task.Description,
-		task.Priority,
-		task.Status,
-		task.DueAt,
-	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
+// taskSortColumns whitelists the columns a task list can be ordered by
+var taskSortColumns = map[string]string{
+	"due_at":     "due_at",
+	"priority":   "priority",
+	"title":      "lower(title)",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+func (r *TaskRepo) ListAllTodos(ctx context.Context, userID string, filter *entity.TaskFilter) ([]*entity.Task, int64, error) {
+	where, err := taskFilterWhere(userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Fetch total count before the keyset condition narrows the rows down
+	var totalCount int64
+	if filter.CountTotal {
+		err = r.dao.QueryRow(ctx, `SELECT COUNT(*) FROM tasks`+where.String(), where.args...).Scan(&totalCount)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
 
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	orderBy, err := taskOrderBy(filter.Sort, backward)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
+	// Add pagination and ordering, a cursor replaces the offset
+	var query string
+	args := where.args
+	if filter.Cursor != nil {
+		keysetWhere(where, filter.Sort, filter.Cursor)
+		args = append(where.args, filter.Limit)
+		query = `SELECT ` + taskColumns + ` FROM tasks` + where.String() +
+			fmt.Sprintf(" ORDER BY %s LIMIT $%d", orderBy, len(args))
+	} else {
+		args = append(where.args, filter.Limit, filter.Offset)
+		query = `SELECT ` + taskColumns + ` FROM tasks` + where.String() +
+			fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", orderBy, len(args)-1, len(args))
+	}
+
+	rows, err := r.dao.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, totalCount, nil
+}
+
+// taskFilterWhere turns a task filter into a parameterised WHERE clause
+func taskFilterWhere(userID string, filter *entity.TaskFilter) (*whereBuilder, error) {
+	where := &whereBuilder{}
+	where.add("user_id = ?", userID)
+	where.add("deleted_at IS NULL")
+
+	// Add status filter if applicable
+	if filter.StatusID != 0 {
+		where.add("status_id = ?", filter.StatusID)
+	}
+
+	if len(filter.Priorities) > 0 {
+		where.add("priority = ANY(?)", filter.Priorities)
+	}
+
+	// Restrict to a single project
+	if filter.ProjectID != nil {
+		where.add("project_id = ?", *filter.ProjectID)
+	}
+
+	// Tasks carrying any of the given tags
+	if len(filter.TagsAny) > 0 {
+		where.add(`id IN (
+			SELECT tt.task_id FROM task_tags tt JOIN tags g ON g.id = tt.tag_id
+			WHERE g.user_id = ? AND g.name = ANY(?))`, userID, filter.TagsAny)
+	}
+
+	// Tasks carrying all of the given tags
+	if len(filter.TagsAll) > 0 {
+		where.add(`id IN (
+			SELECT tt.task_id FROM task_tags tt JOIN tags g ON g.id = tt.tag_id
+			WHERE g.user_id = ? AND g.name = ANY(?)
+			GROUP BY tt.task_id HAVING COUNT(DISTINCT g.id) = ?)`, userID, filter.TagsAll, len(filter.TagsAll))
+	}
+
+	// Restrict to the direct children of a task, or to top level tasks only
+	if filter.ParentID != nil {
+		where.add("parent_id = ?", *filter.ParentID)
+	} else if filter.RootsOnly {
+		where.add("parent_id IS NULL")
+	}
+
+	// Open tasks that are not waiting on an open blocker
+	if filter.Actionable {
+		where.add(`status_id IN ` + openStatuses + ` AND NOT EXISTS (
+			SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by_id
+			WHERE d.task_id = tasks.id AND b.status_id IN ` + openStatuses + ` AND b.deleted_at IS NULL)`)
+	}
+
+	// Due date filters
+	if filter.NoDueDate {
+		where.add("due_at IS NULL")
+	}
+	if filter.DueFrom != nil {
+		where.add("due_at >= ?", *filter.DueFrom)
+	}
+	if filter.DueTo != nil {
+		where.add("due_at <= ?", *filter.DueTo)
+	}
+	if filter.Overdue {
+		where.add("due_at < now() AND status_id IN " + openStatuses)
+	}
+
+	if filter.CreatedFrom != nil {
+		where.add("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		where.add("created_at <= ?", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		where.add("updated_at >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		where.add("updated_at <= ?", *filter.UpdatedTo)
+	}
+
+	return where, nil
+}
+
+// defaultTaskSort is used when a list request does not ask for a specific order
+var defaultTaskSort = []entity.SortKey{{Field: "created_at", Desc: true}}
+
+// taskOrderBy builds the ORDER BY list from whitelisted columns, newest first by default.
+// id is always appended so the order is total and stable between pages. backward
+// mirrors the whole order, it is used to read the page before a cursor.
+func taskOrderBy(keys []entity.SortKey, backward bool) (string, error) {
+	if len(keys) == 0 {
+		keys = defaultTaskSort
+	}
+
+	parts := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		column, ok := taskSortColumns[key.Field]
+		if !ok {
+			return "", fmt.Errorf("invalid sort field: %s", key.Field)
+		}
+		direction, nulls := "ASC", "NULLS LAST"
+		if key.Desc != backward {
+			direction = "DESC"
+		}
+		if backward {
+			nulls = "NULLS FIRST"
+		}
+		parts = append(parts, column+" "+direction+" "+nulls)
+	}
+	if backward {
+		parts = append(parts, "id ASC")
+	} else {
+		parts = append(parts, "id DESC")
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// keysetWhere restricts the rows to those strictly after the cursor in the order built by
+// taskOrderBy, i.e. (k1 after c1) OR (k1 = c1 AND k2 after c2) OR ... OR (all equal AND id after).
+func keysetWhere(where *whereBuilder, keys []entity.SortKey, cursor *entity.Cursor) {
+	if len(keys) == 0 {
+		keys = defaultTaskSort
+	}
+
+	var (
+		branches []string
+		equal    []string
+		args     []interface{}
+		eqArgs   []interface{}
+	)
+	for _, key := range keys {
+		column := taskSortColumns[key.Field]
+		value := cursorValue(key.Field, cursor)
+		desc := key.Desc != cursor.Backward
+		nullsLast := !cursor.Backward
+
+		// the condition for "this column comes after the cursor value"
+		var after string
+		var afterArgs []interface{}
+		switch {
+		case value == nil && nullsLast:
+			after = "FALSE"
+		case value == nil:
+			after = column + " IS NOT NULL"
+		default:
+			op := ">"
+			if desc {
+				op = "<"
+			}
+			after = column + " " + op + " ?"
+			if nullsLast {
+				after = "(" + after + " OR " + column + " IS NULL)"
+			}
+			afterArgs = []interface{}{value}
+		}
+
+		branches = append(branches, strings.Join(append(append([]string{}, equal...), after), " AND "))
+		args = append(append(args, eqArgs...), afterArgs...)
+
+		if value == nil {
+			equal = append(equal, column+" IS NULL")
+		} else {
+			equal = append(equal, column+" = ?")
+			eqArgs = append(eqArgs, value)
+		}
+	}
+
+	idOp := "<"
+	if cursor.Backward {
+		idOp = ">"
+	}
+	branches = append(branches, strings.Join(append(equal, "id "+idOp+" ?"), " AND "))
+	args = append(append(args, eqArgs...), cursor.ID)
+
+	where.add("(("+strings.Join(branches, ") OR (")+"))", args...)
+}
+
+// cursorValue returns the cursor value compared against the column of a sort field
+func cursorValue(field string, cursor *entity.Cursor) interface{} {
+	switch field {
+	case "due_at":
+		if cursor.DueAt == nil {
+			return nil
+		}
+		return *cursor.DueAt
+	case "priority":
+		return cursor.Priority
+	case "title":
+		return strings.ToLower(cursor.Title)
+	case "created_at":
+		return cursor.CreatedAt
+	case "updated_at":
+		return cursor.UpdatedAt
+	}
 	return nil
 }
 
-func (r *TaskRepo) ListAllTodos(ctx context.Context, userID string, statusFilter string, limit, offset int) ([]*entity.Task, int64, error) {
-	baseQuery := `
-		SELECT
-			id, user_id, title, description, priority, status, created_at, updated_at, due_at
-		FROM
-			tasks
+// SearchTodos runs a ranked full-text search over the title and description of a user's tasks
+func (r *TaskRepo) SearchTodos(ctx context.Context, userID, query string, statusID int64, limit, offset int) ([]*entity.SearchHit, int64, error) {
+	where := `
 		WHERE
 			user_id = $1
+			AND deleted_at IS NULL
+			AND search_vector @@ websearch_to_tsquery('english', $2)
 	`
-	countQuery := `
-		SELECT COUNT(*) FROM tasks WHERE user_id = $1
-	`
-
-	args := []interface{}{userID}
-	argIndex := 2
+	args := []interface{}{userID, query}
+	argIndex := 3
 
-	// Add status filter if applicable
-	if strings.ToUpper(statusFilter) != "ALL" && statusFilter != "" {
-		statusVal, ok := globals.TaskStatus[statusFilter]
-		if !ok {
-			return nil, 0, fmt.Errorf("invalid status filter: %s", statusFilter)
-		}
-		baseQuery += fmt.Sprintf(" AND status = $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND status = $%d", argIndex)
-		args = append(args, statusVal)
+	if statusID != 0 {
+		where += fmt.Sprintf(" AND status_id = $%d", argIndex)
+		args = append(args, statusID)
 		argIndex++
 	}
 
-	// Add pagination and ordering
-	baseQuery += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, limit, offset)
+	searchQuery := `
+		SELECT
+			` + taskColumns + `,
+			ts_rank_cd(search_vector, websearch_to_tsquery('english', $2)) AS rank,
+			ts_headline('english', title, websearch_to_tsquery('english', $2),
+				'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+			ts_headline('english', coalesce(description, ''), websearch_to_tsquery('english', $2),
+				'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2')
+		FROM
+			tasks
+	` + where + fmt.Sprintf(`
+		ORDER BY rank DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, argIndex, argIndex+1)
 
-	rows, err := r.dao.Query(ctx, baseQuery, args...)
+	rows, err := r.dao.Query(ctx, searchQuery, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var tasks []*entity.Task
+	var hits []*entity.SearchHit
 	for rows.Next() {
 		var (
-			id, priority, status        sql.NullInt32
-			userID, title, description  sql.NullString
-			createdAt, updatedAt, dueAt sql.NullTime
+			hit                 entity.SearchHit
+			titleSnip, descSnip sql.NullString
 		)
-
-		if err := rows.Scan(
-			&id,
-			&userID,
-			&title,
-			&description,
-			&priority,
-			&status,
-			&createdAt,
-			&updatedAt,
-			&dueAt,
-		); err != nil {
+		task, err := scanTask(searchRow{rows: rows, extra: []any{&hit.Rank, &titleSnip, &descSnip}})
+		if err != nil {
 			return nil, 0, err
 		}
-
-		task := &entity.Task{
-			ID:          int64(id.Int32),
-			UserID:      userID.String,
-			Title:       title.String,
-			Description: description.String,
-			Priority:    int(priority.Int32),
-			Status:      int(status.Int32),
-			CreatedAt:   createdAt.Time,
-			UpdatedAt:   updatedAt.Time,
-		}
-		if dueAt.Valid {
-			task.DueAt = dueAt.Time
-		}
-		tasks = append(tasks, task)
+		hit.Task = task
+		hit.TitleSnippet = titleSnip.String
+		hit.DescriptionSnippet = descSnip.String
+		hits = append(hits, &hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	// Fetch total count
 	var totalCount int64
-	err = r.dao.QueryRow(ctx, countQuery, args[:len(args)-2]...).Scan(&totalCount)
-	if err != nil {
+	if err := r.dao.QueryRow(ctx, `SELECT COUNT(*) FROM tasks `+where, args...).Scan(&totalCount); err != nil {
 		return nil, 0, err
 	}
+	return hits, totalCount, nil
+}
 
-	return tasks, totalCount, nil
+// searchRow lets scanTask read a row that carries extra columns after taskColumns
+type searchRow struct {
+	rows  pgx.Rows
+	extra []any
+}
+
+func (s searchRow) Scan(dest ...any) error {
+	return s.rows.Scan(append(dest, s.extra...)...)
 }
 
-func (r *TaskRepo) UpdateTodoByID(ctx context.Context, id int, updatedTask *entity.Task) error {
+// ListSubtasks returns every descendant of the given tasks, at any depth, oldest first
+func (r *TaskRepo) ListSubtasks(ctx context.Context, userID string, parentIDs []int64) ([]*entity.Task, error) {
 	query := `
-		UPDATE tasks
-		SET
-			title = $1,
-			description = $2,
-			priority = $3,
-			status = $4,
-			due_at = $5,
-			updated_at = now()
-		WHERE id = $6 AND user_id = $7
+		WITH RECURSIVE tree AS (
+			SELECT id FROM tasks WHERE user_id = $1 AND parent_id = ANY($2) AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at IS NULL
+		)
+		SELECT
+			` + taskColumns + `
+		FROM
+			tasks
+		WHERE
+			id IN (SELECT id FROM tree)
+		ORDER BY created_at ASC
 	`
 
-	cmdTag, err := r.dao.Exec(
-		ctx,
-		query,
-		updatedTask.Title,
-		updatedTask.Description,
-		updatedTask.Priority,
-		updatedTask.Status,
-		updatedTask.DueAt,
-		id,
-		updatedTask.UserID,
-	)
+	rows, err := r.dao.Query(ctx, query, userID, parentIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return scanTasks(rows)
+}
+
+// CountOpenSubtasks counts the direct children of a task that are not completed yet
+func (r *TaskRepo) CountOpenSubtasks(ctx context.Context, id int) (int64, error) {
+	query := `SELECT COUNT(*) FROM tasks WHERE parent_id = $1 AND status_id IN ` + openStatuses + ` AND deleted_at IS NULL`
 
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("no rows updated — invalid id or user_id mismatch")
+	var count int64
+	err := r.dao.QueryRow(ctx, query, id).Scan(&count)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	return count, nil
 }
 
-// This should be a soft delete, for now im keeping it as DELET operation
-func (r *TaskRepo) DeleteTodo(ctx context.Context, id int) error {
-	query := `DELETE FROM tasks WHERE id = $1`
-	_, err := r.dao.Exec(ctx, query, id)
-	return err
+// ListStatusChanges returns the status history of a task, oldest first
+func (r *TaskRepo) ListStatusChanges(ctx context.Context, id int) ([]*entity.StatusChange, error) {
+	query := `
+		SELECT h.task_id, h.from_status_id, h.to_status_id, f.name, t.name, t.is_terminal, h.changed_at
+		FROM task_status_history h
+		LEFT JOIN statuses f ON f.id = h.from_status_id
+		LEFT JOIN statuses t ON t.id = h.to_status_id
+		WHERE h.task_id = $1
+		ORDER BY h.changed_at ASC, h.id ASC
+	`
+
+	rows, err := r.dao.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*entity.StatusChange
+	for rows.Next() {
+		var (
+			fromID, toID     sql.NullInt64
+			fromName, toName sql.NullString
+			terminal         sql.NullBool
+		)
+		change := &entity.StatusChange{}
+		if err := rows.Scan(&change.TaskID, &fromID, &toID, &fromName, &toName, &terminal, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if fromID.Valid {
+			change.FromStatusID = &fromID.Int64
+		}
+		if toID.Valid {
+			change.ToStatusID = &toID.Int64
+		}
+		change.FromStatus = fromName.String
+		change.ToStatus = toName.String
+		change.Terminal = terminal.Bool
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
 }
 
 func (r *TaskRepo) GetTodoByID(ctx context.Context, id int) (*entity.Task, error) {
 	query := `
 		SELECT
-			id,
-			user_id,
-			title,
-			description,
-			priority,
-			status,
-			due_at,
-			created_at,
-			updated_at
+			` + taskColumns + `
 		FROM tasks
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var (
-		taskID, priority, status    sql.NullInt32
-		userID, title, description  sql.NullString
-		dueAt, createdAt, updatedAt sql.NullTime
-	)
+	return scanTask(r.dao.QueryRow(ctx, query, id))
+}
 
-	err := r.dao.QueryRow(ctx, query, id).Scan(
-		&taskID,
-		&userID,
-		&title,
-		&description,
-		&priority,
-		&status,
-		&dueAt,
-		&createdAt,
-		&updatedAt,
-	)
+// GetTrashedTodoByID fetches a task only if it is in the trash
+func (r *TaskRepo) GetTrashedTodoByID(ctx context.Context, id int) (*entity.Task, error) {
+	query := `
+		SELECT
+			` + taskColumns + `
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	return scanTask(r.dao.QueryRow(ctx, query, id))
+}
+
+// ListTrash returns the tasks a user deleted, most recently deleted first. Subtasks that
+// were deleted together with their parent are not listed on their own.
+func (r *TaskRepo) ListTrash(ctx context.Context, userID string, limit, offset int) ([]*entity.Task, int64, error) {
+	where := `
+		WHERE t.user_id = $1 AND t.deleted_at IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM tasks p WHERE p.id = t.parent_id AND p.deleted_at = t.deleted_at
+		)
+	`
+	query := `
+		SELECT
+			` + taskColumns + `
+		FROM tasks t
+	` + where + `
+		ORDER BY t.deleted_at DESC, t.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.dao.Query(ctx, query, userID, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	task := &entity.Task{
-		ID:          int64(taskID.Int32),
-		UserID:      userID.String,
-		Title:       title.String,
-		Description: description.String,
-		Priority:    int(priority.Int32),
-		Status:      int(status.Int32),
-		DueAt:       dueAt.Time,
-		CreatedAt:   createdAt.Time,
-		UpdatedAt:   updatedAt.Time,
+	var totalCount int64
+	if err := r.dao.QueryRow(ctx, `SELECT COUNT(*) FROM tasks t `+where, userID).Scan(&totalCount); err != nil {
+		return nil, 0, err
 	}
+	return tasks, totalCount, nil
+}
 
-	return task, nil
+// PurgeTodo permanently deletes a trashed task, its subtasks go with it through the foreign key
+func (r *TaskRepo) PurgeTodo(ctx context.Context, id int) error {
+	query := `DELETE FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL`
+	_, err := r.dao.Exec(ctx, query, id)
+	return err
+}
+
+// PurgeTrash permanently deletes every task that has been in the trash since before the given time
+func (r *TaskRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	cmdTag, err := r.dao.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
 }
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+)
+
+func TestKeysetWhere(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		keys     []entity.SortKey
+		cursor   *entity.Cursor
+		wantCond string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "default sort breaks ties on id",
+			cursor:   &entity.Cursor{ID: 42, CreatedAt: created},
+			wantCond: "(((created_at < $1 OR created_at IS NULL)) OR (created_at = $2 AND id < $3))",
+			wantArgs: []interface{}{created, created, int64(42)},
+		},
+		{
+			name:     "backward mirrors the comparisons",
+			cursor:   &entity.Cursor{ID: 42, CreatedAt: created, Backward: true},
+			wantCond: "((created_at > $1) OR (created_at = $2 AND id > $3))",
+			wantArgs: []interface{}{created, created, int64(42)},
+		},
+		{
+			name:     "two keys",
+			keys:     []entity.SortKey{{Field: "priority", Desc: true}, {Field: "title"}},
+			cursor:   &entity.Cursor{ID: 7, Priority: 2, Title: "Buy Milk"},
+			wantCond: "(((priority < $1 OR priority IS NULL)) OR (priority = $2 AND (lower(title) > $3 OR lower(title) IS NULL)) OR (priority = $4 AND lower(title) = $5 AND id < $6))",
+			wantArgs: []interface{}{2, 2, "buy milk", 2, "buy milk", int64(7)},
+		},
+		{
+			name:     "due date set",
+			keys:     []entity.SortKey{{Field: "due_at"}},
+			cursor:   &entity.Cursor{ID: 7, DueAt: &due},
+			wantCond: "(((due_at > $1 OR due_at IS NULL)) OR (due_at = $2 AND id < $3))",
+			wantArgs: []interface{}{due, due, int64(7)},
+		},
+		{
+			name:     "null due date sorts last",
+			keys:     []entity.SortKey{{Field: "due_at"}},
+			cursor:   &entity.Cursor{ID: 7},
+			wantCond: "((FALSE) OR (due_at IS NULL AND id < $1))",
+			wantArgs: []interface{}{int64(7)},
+		},
+		{
+			name:     "null due date backward",
+			keys:     []entity.SortKey{{Field: "due_at"}},
+			cursor:   &entity.Cursor{ID: 7, Backward: true},
+			wantCond: "((due_at IS NOT NULL) OR (due_at IS NULL AND id > $1))",
+			wantArgs: []interface{}{int64(7)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where := &whereBuilder{}
+			keysetWhere(where, tt.keys, tt.cursor)
+
+			if len(where.conds) != 1 || where.conds[0] != tt.wantCond {
+				t.Errorf("condition = %q, want %q", where.conds, tt.wantCond)
+			}
+			if !reflect.DeepEqual(where.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", where.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestTaskOrderBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []entity.SortKey
+		backward bool
+		want     string
+	}{
+		{"default", nil, false, "created_at DESC NULLS LAST, id DESC"},
+		{"default backward", nil, true, "created_at ASC NULLS FIRST, id ASC"},
+		{"two keys", []entity.SortKey{{Field: "priority", Desc: true}, {Field: "title"}}, false,
+			"priority DESC NULLS LAST, lower(title) ASC NULLS LAST, id DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := taskOrderBy(tt.keys, tt.backward)
+			if err != nil {
+				t.Fatalf("taskOrderBy error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("taskOrderBy = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := taskOrderBy([]entity.SortKey{{Field: "id; DROP TABLE tasks"}}, false); err == nil {
+		t.Error("taskOrderBy accepted an unknown field")
+	}
+}
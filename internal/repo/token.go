@@ -0,0 +1,224 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+)
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, access_jti, access_expires_at, expires_at, created_at, used_at, revoked_at`
+
+// insertRefreshTokenQuery stores a refresh token and returns its id and creation time
+const insertRefreshTokenQuery = `
+	INSERT INTO refresh_tokens (user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, created_at
+`
+
+type TokenRepo struct {
+	dao *pgxpool.Pool
+}
+
+func NewTokenRepository(dao *pgxpool.Pool) interfaces.TokenRepoInterface {
+	return &TokenRepo{
+		dao: dao,
+	}
+}
+
+func scanRefreshToken(row pgx.Row) (*entity.RefreshToken, error) {
+	var (
+		id                                     sql.NullInt64
+		userID, familyID, tokenHash, accessJTI sql.NullString
+		accessExpiresAt, expiresAt, createdAt  sql.NullTime
+		usedAt, revokedAt                      sql.NullTime
+	)
+
+	if err := row.Scan(&id, &userID, &familyID, &tokenHash, &accessJTI, &accessExpiresAt, &expiresAt,
+		&createdAt, &usedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	return &entity.RefreshToken{
+		ID:              id.Int64,
+		UserID:          userID.String,
+		FamilyID:        familyID.String,
+		TokenHash:       tokenHash.String,
+		AccessJTI:       accessJTI.String,
+		AccessExpiresAt: accessExpiresAt.Time,
+		ExpiresAt:       expiresAt.Time,
+		CreatedAt:       createdAt.Time,
+		UsedAt:          nullTime(usedAt.Time),
+		RevokedAt:       nullTime(revokedAt.Time),
+	}, nil
+}
+
+func scanRefreshTokens(rows pgx.Rows) ([]*entity.RefreshToken, error) {
+	defer rows.Close()
+
+	var tokens []*entity.RefreshToken
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *TokenRepo) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	return r.dao.QueryRow(ctx, insertRefreshTokenQuery, token.UserID, token.FamilyID, token.TokenHash,
+		token.AccessJTI, token.AccessExpiresAt, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+}
+
+func (r *TokenRepo) GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+	return scanRefreshToken(r.dao.QueryRow(ctx, query, hash))
+}
+
+// GetRefreshTokenByAccessJTI returns the refresh token issued together with an access token
+func (r *TokenRepo) GetRefreshTokenByAccessJTI(ctx context.Context, jti string) (*entity.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE access_jti = $1`
+	return scanRefreshToken(r.dao.QueryRow(ctx, query, jti))
+}
+
+// RotateRefreshToken marks a token as used and stores the one replacing it in a single
+// transaction. It returns pgx.ErrNoRows when the token was used or revoked in the meantime,
+// so two concurrent refreshes with the same token cannot both succeed.
+func (r *TokenRepo) RotateRefreshToken(ctx context.Context, usedID int64, next *entity.RefreshToken) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET used_at = now()
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`, usedID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if err := tx.QueryRow(ctx, insertRefreshTokenQuery, next.UserID, next.FamilyID, next.TokenHash,
+		next.AccessJTI, next.AccessExpiresAt, next.ExpiresAt).Scan(&next.ID, &next.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// RevokeFamily revokes every token of a login and returns the ones it revoked
+func (r *TokenRepo) RevokeFamily(ctx context.Context, familyID string) ([]*entity.RefreshToken, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = now()
+		WHERE family_id = $1 AND revoked_at IS NULL
+		RETURNING ` + refreshTokenColumns
+
+	rows, err := r.dao.Query(ctx, query, familyID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRefreshTokens(rows)
+}
+
+// RevokeUserTokens revokes every token of every login of a user and returns the ones it revoked
+func (r *TokenRepo) RevokeUserTokens(ctx context.Context, userID string) ([]*entity.RefreshToken, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = now()
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING ` + refreshTokenColumns
+
+	rows, err := r.dao.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRefreshTokens(rows)
+}
+
+// DeleteExpiredRefreshTokens removes the tokens of a user that can no longer be used
+func (r *TokenRepo) DeleteExpiredRefreshTokens(ctx context.Context, userID string) error {
+	_, err := r.dao.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1 AND expires_at < now()`, userID)
+	return err
+}
+
+// CreatePasswordReset stores a reset token of a user, tokens requested before it stop working
+func (r *TokenRepo) CreatePasswordReset(ctx context.Context, userID, hash string, expiresAt time.Time) error {
+	return r.createOneTimeToken(ctx, "password_resets", userID, hash, expiresAt)
+}
+
+// ConsumePasswordReset uses up a reset token that is still valid and returns its user. It
+// returns pgx.ErrNoRows for an unknown, used or expired token.
+func (r *TokenRepo) ConsumePasswordReset(ctx context.Context, hash string) (string, error) {
+	query := `
+		UPDATE password_resets
+		SET used_at = now()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id
+	`
+
+	var userID string
+	err := r.dao.QueryRow(ctx, query, hash).Scan(&userID)
+	return userID, err
+}
+
+// CreateEmailVerification stores a verification token of a user, tokens sent before it stop working
+func (r *TokenRepo) CreateEmailVerification(ctx context.Context, userID, hash string, expiresAt time.Time) error {
+	return r.createOneTimeToken(ctx, "email_verifications", userID, hash, expiresAt)
+}
+
+// VerifyEmail uses up a verification token that is still valid and marks the email of its user
+// as verified in the same statement. It returns the user, or pgx.ErrNoRows for an unknown, used
+// or expired token.
+func (r *TokenRepo) VerifyEmail(ctx context.Context, hash string) (string, error) {
+	query := `
+		WITH verification AS (
+			UPDATE email_verifications
+			SET used_at = now()
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id
+		)
+		UPDATE users u
+		SET
+			email_verified_at = COALESCE(u.email_verified_at, now()),
+			updated_at = now()
+		FROM verification v
+		WHERE u.id = v.user_id
+		RETURNING u.id
+	`
+
+	var userID string
+	err := r.dao.QueryRow(ctx, query, hash).Scan(&userID)
+	return userID, err
+}
+
+// createOneTimeToken stores a token in one of the single-use token tables, the unused tokens
+// the user already had there are used up so only the latest one works
+func (r *TokenRepo) createOneTimeToken(ctx context.Context, table, userID, hash string, expiresAt time.Time) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE `+table+` SET used_at = now() WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO `+table+` (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`, userID, hash, expiresAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
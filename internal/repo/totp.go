@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetTOTPSecret stores the secret of a pending enrollment, replacing an earlier unconfirmed one.
+// It returns pgx.ErrNoRows when two-factor authentication is already on.
+func (r *UserRepo) SetTOTPSecret(ctx context.Context, userID, secret string) error {
+	query := `
+		UPDATE users
+		SET
+			totp_secret = $1,
+			updated_at = now()
+		WHERE id = $2 AND totp_enabled_at IS NULL
+	`
+	tag, err := r.dao.Exec(ctx, query, secret, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// EnableTOTP turns two-factor authentication on with the confirmed secret and replaces the
+// recovery codes of the user. step is the time step of the confirming code, so it cannot be
+// used to log in. It returns pgx.ErrNoRows when it is already on.
+func (r *UserRepo) EnableTOTP(ctx context.Context, userID string, step int64, codeHashes []string) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE users
+		SET
+			totp_enabled_at = now(),
+			totp_last_step = $1,
+			updated_at = now()
+		WHERE id = $2 AND totp_enabled_at IS NULL AND totp_secret IS NOT NULL
+	`, step, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO recovery_codes (user_id, code_hash)
+		SELECT $1, unnest($2::text[])
+	`, userID, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DisableTOTP turns two-factor authentication off and removes the secret and recovery codes
+func (r *UserRepo) DisableTOTP(ctx context.Context, userID string) error {
+	tx, err := r.dao.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE users
+		SET
+			totp_secret = NULL,
+			totp_enabled_at = NULL,
+			totp_last_step = 0,
+			updated_at = now()
+		WHERE id = $1
+	`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// AcceptTOTPStep records that a code of the given time step was used. It returns pgx.ErrNoRows
+// when a code of that step or a later one was accepted already, so a code works only once.
+func (r *UserRepo) AcceptTOTPStep(ctx context.Context, userID string, step int64) error {
+	tag, err := r.dao.Exec(ctx, `UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1`, step, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// UseRecoveryCode uses up an unused recovery code of a user, it returns pgx.ErrNoRows when the
+// user has no such code
+func (r *UserRepo) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	query := `
+		UPDATE recovery_codes
+		SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	tag, err := r.dao.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -36,29 +36,43 @@ func (r *UserRepo) GetUserByID(ctx context.Context, ID string) (*entity.User, er
 		SELECT
 			id, 
 			username, 
-			email
+			email,
+			email_verified_at,
+			totp_secret,
+			totp_enabled_at,
+			totp_last_step
 		FROM
 			users
 		WHERE
 			id = $1
 	`
 	var (
-		id, username, email sql.NullString
+		id, username, email, totpSecret sql.NullString
+		verifiedAt, totpEnabledAt       sql.NullTime
+		totpLastStep                    sql.NullInt64
 	)
 
 	err := r.dao.QueryRow(ctx, query, ID).Scan(
 		&id,
 		&username,
 		&email,
+		&verifiedAt,
+		&totpSecret,
+		&totpEnabledAt,
+		&totpLastStep,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	user := &entity.User{
-		ID:       id.String,
-		UserName: username.String,
-		Email:    email.String,
+		ID:              id.String,
+		UserName:        username.String,
+		Email:           email.String,
+		EmailVerifiedAt: nullTime(verifiedAt.Time),
+		TOTPSecret:      totpSecret.String,
+		TOTPEnabledAt:   nullTime(totpEnabledAt.Time),
+		TOTPLastStep:    totpLastStep.Int64,
 	}
 	return user, nil
 }
@@ -69,14 +83,20 @@ func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*entity.Us
 			id, 
 			username, 
 			email,
-			password
+			password,
+			email_verified_at,
+			totp_secret,
+			totp_enabled_at,
+			totp_last_step
 		FROM 
 			users
 		WHERE 
 			email = $1
 	`
 	var (
-		id, username, dbEmail, password sql.NullString
+		id, username, dbEmail, password, totpSecret sql.NullString
+		verifiedAt, totpEnabledAt                   sql.NullTime
+		totpLastStep                                sql.NullInt64
 	)
 
 	err := r.dao.QueryRow(ctx, query, email).Scan(
@@ -84,17 +104,38 @@ func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*entity.Us
 		&username,
 		&dbEmail,
 		&password,
+		&verifiedAt,
+		&totpSecret,
+		&totpEnabledAt,
+		&totpLastStep,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	user := &entity.User{
-		ID:       id.String,
-		UserName: username.String,
-		Email:    dbEmail.String,
-		Password: password.String,
+		ID:              id.String,
+		UserName:        username.String,
+		Email:           dbEmail.String,
+		Password:        password.String,
+		EmailVerifiedAt: nullTime(verifiedAt.Time),
+		TOTPSecret:      totpSecret.String,
+		TOTPEnabledAt:   nullTime(totpEnabledAt.Time),
+		TOTPLastStep:    totpLastStep.Int64,
 	}
 
 	return user, nil
 }
+
+// UpdatePassword replaces the hashed password of a user
+func (r *UserRepo) UpdatePassword(ctx context.Context, userID, password string) error {
+	query := `
+		UPDATE users
+		SET
+			password = $1,
+			updated_at = now()
+		WHERE id = $2
+	`
+	_, err := r.dao.Exec(ctx, query, password, userID)
+	return err
+}
@@ -1,28 +1,97 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/shivarajshanthaiah/todo-app/configs"
+	"github.com/shivarajshanthaiah/todo-app/internal/clients/redis"
 	"github.com/shivarajshanthaiah/todo-app/internal/handler"
 	"github.com/shivarajshanthaiah/todo-app/internal/middleware"
 )
 
-func RegisterRoutes(router *gin.Engine, todoHndlr *handler.TaskHandler, userHndlr *handler.UserHandler, cnfg *configs.Config) {
+func RegisterRoutes(router *gin.Engine, todoHndlr *handler.TaskHandler, userHndlr *handler.UserHandler, projectHndlr *handler.ProjectHandler, tagHndlr *handler.TagHandler, statusHndlr *handler.StatusHandler, commentHndlr *handler.CommentHandler, attachmentHndlr *handler.AttachmentHandler, redisSvc *redis.RedisService, cnfg *configs.Config) {
 
 	v1 := router.Group("/api/v1")
 	{
 		v1.POST("/signup", userHndlr.UserSignUpHandler)
 		v1.POST("/login", userHndlr.UserLoginHandler)
+		v1.POST("/login/2fa", userHndlr.LoginTwoFactorHandler)
+		v1.POST("/refresh", userHndlr.RefreshTokenHandler)
+		v1.POST("/password/forgot", userHndlr.ForgotPasswordHandler)
+		v1.POST("/password/reset", userHndlr.ResetPasswordHandler)
+		v1.POST("/email/verify", userHndlr.VerifyEmailHandler)
+		v1.POST("/email/verify/resend", userHndlr.ResendVerificationHandler)
+		v1.POST("/logout", middleware.Authorization(cnfg.SECRETKEY, redisSvc), userHndlr.LogoutHandler)
+		v1.POST("/logout/all", middleware.Authorization(cnfg.SECRETKEY, redisSvc), userHndlr.LogoutAllHandler)
 	}
 
+	// the multipart envelope of an upload gets some room on top of the file itself, no request
+	// body may be larger, including the ones buffered for an Idempotency-Key
+	maxBody := cnfg.AttachmentMaxBytes + 1<<20
+
 	user := v1.Group("user")
-	user.Use(middleware.Authorization(cnfg.SECRETKEY))
+	user.Use(middleware.Authorization(cnfg.SECRETKEY, redisSvc))
+	user.Use(middleware.Idempotency(redisSvc, time.Duration(cnfg.IdempotencyWindowHours)*time.Hour, maxBody))
 	{
-		user.POST("/todos", todoHndlr.CreateTodoHandler)
+		// with REQUIRE_VERIFIED_TASKS unverified accounts can still read their data and manage
+		// their account, everything that changes tasks, projects, statuses or tags is refused
+		verified := userHndlr.RequireVerifiedEmail
+
+		user.POST("/todos", verified, todoHndlr.CreateTodoHandler)
 		user.POST("/todos/list", todoHndlr.GetTodosHandler)
-		user.PATCH("/todos/:id", todoHndlr.UpdateTodoHandler)
-		// user.PUT("/todos", todoHndlr.UpdateTodoHandler)
-		user.DELETE("/todos/:id", todoHndlr.DeleteTodoHandler)
+		user.POST("/todos/search", todoHndlr.SearchTodosHandler)
+		user.POST("/todos/bulk", verified, todoHndlr.BulkTodosHandler)
+		user.GET("/todos/:id", todoHndlr.GetTodoHandler)
+		user.PATCH("/todos/:id", verified, todoHndlr.PatchTodoHandler)
+		user.PUT("/todos/:id", verified, todoHndlr.UpdateTodoHandler)
+		user.DELETE("/todos/:id", verified, todoHndlr.DeleteTodoHandler)
+		user.GET("/todos/trash", todoHndlr.ListTrashHandler)
+		user.POST("/todos/:id/restore", verified, todoHndlr.RestoreTodoHandler)
+		user.DELETE("/todos/:id/purge", verified, todoHndlr.PurgeTodoHandler)
+		user.POST("/todos/:id/dependencies", verified, todoHndlr.AddDependencyHandler)
+		user.DELETE("/todos/:id/dependencies/:blocker_id", verified, todoHndlr.RemoveDependencyHandler)
+		user.POST("/todos/:id/checklist", verified, todoHndlr.AddChecklistItemHandler)
+		user.PUT("/todos/:id/checklist/order", verified, todoHndlr.ReorderChecklistHandler)
+		user.PATCH("/todos/:id/checklist/:item_id", verified, todoHndlr.UpdateChecklistItemHandler)
+		user.DELETE("/todos/:id/checklist/:item_id", verified, todoHndlr.DeleteChecklistItemHandler)
+		user.GET("/todos/:id/transitions", todoHndlr.GetStatusHistoryHandler)
+		user.GET("/todos/:id/history", todoHndlr.GetTodoHistoryHandler)
+		user.POST("/todos/:id/revert/:revision", verified, todoHndlr.RevertTodoHandler)
+		user.PUT("/todos/:id/recurrence", verified, todoHndlr.UpdateRecurrenceHandler)
+		user.DELETE("/todos/:id/recurrence", verified, todoHndlr.CancelRecurrenceHandler)
 		user.GET("/get/profile", userHndlr.GetUserProfileHandler)
+		user.GET("/sessions", userHndlr.ListSessionsHandler)
+		user.DELETE("/sessions/:id", userHndlr.RevokeSessionHandler)
+		user.GET("/login-history", userHndlr.LoginHistoryHandler)
+		user.POST("/2fa/enroll", userHndlr.EnrollTwoFactorHandler)
+		user.POST("/2fa/confirm", userHndlr.ConfirmTwoFactorHandler)
+		user.POST("/2fa/disable", userHndlr.DisableTwoFactorHandler)
+
+		user.GET("/todos/:id/comments", commentHndlr.ListCommentsHandler)
+		user.POST("/todos/:id/comments", verified, commentHndlr.AddCommentHandler)
+		user.PATCH("/todos/:id/comments/:comment_id", verified, commentHndlr.UpdateCommentHandler)
+		user.DELETE("/todos/:id/comments/:comment_id", verified, commentHndlr.DeleteCommentHandler)
+
+		uploadLimit := middleware.MaxBodySize(maxBody)
+		user.GET("/todos/:id/attachments", attachmentHndlr.ListAttachmentsHandler)
+		user.POST("/todos/:id/attachments", verified, uploadLimit, attachmentHndlr.UploadAttachmentHandler)
+		user.GET("/todos/:id/attachments/:attachment_id", attachmentHndlr.DownloadAttachmentHandler)
+		user.DELETE("/todos/:id/attachments/:attachment_id", verified, attachmentHndlr.DeleteAttachmentHandler)
+
+		user.POST("/projects", verified, projectHndlr.CreateProjectHandler)
+		user.GET("/projects", projectHndlr.ListProjectsHandler)
+		user.PATCH("/projects/:id", verified, projectHndlr.UpdateProjectHandler)
+		user.DELETE("/projects/:id", verified, projectHndlr.DeleteProjectHandler)
+
+		user.GET("/statuses", statusHndlr.ListStatusesHandler)
+		user.POST("/statuses", verified, statusHndlr.CreateStatusHandler)
+		user.PATCH("/statuses/:id", verified, statusHndlr.UpdateStatusHandler)
+		user.DELETE("/statuses/:id", verified, statusHndlr.DeleteStatusHandler)
+		user.PUT("/statuses/:id/transitions", verified, statusHndlr.SetTransitionsHandler)
+
+		user.GET("/tags", tagHndlr.ListTagsHandler)
+		user.PATCH("/tags/:id", verified, tagHndlr.RenameTagHandler)
+		user.POST("/tags/:id/merge", verified, tagHndlr.MergeTagsHandler)
 	}
 }
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/configs"
+	"github.com/shivarajshanthaiah/todo-app/internal/clients/storage"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	repo "github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+	service "github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+	"go.uber.org/zap"
+)
+
+const (
+	// sniffLength is the number of bytes http.DetectContentType looks at
+	sniffLength = 512
+	// maxFilenameLength limits the number of characters kept of an uploaded file name
+	maxFilenameLength = 255
+	// orphanSweepBatch is the number of orphaned attachments removed per sweep
+	orphanSweepBatch = 100
+)
+
+// allowedAttachmentTypes are the media types, as sniffed from the content, that can be uploaded.
+// Types a browser would render as a page, such as HTML, are left out on purpose.
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":          true,
+	"image/jpeg":         true,
+	"image/gif":          true,
+	"image/webp":         true,
+	"image/bmp":          true,
+	"application/pdf":    true,
+	"application/zip":    true,
+	"application/x-gzip": true,
+	"text/plain":         true,
+	"audio/mpeg":         true,
+	"video/mp4":          true,
+}
+
+type AttachmentService struct {
+	repo       repo.AttachmentRepoInterface
+	tasks      repo.TaskRepoInterface
+	store      storage.Storage
+	maxBytes   int64
+	quotaBytes int64
+	logger     *zap.Logger
+}
+
+func NewAttachmentService(repo repo.AttachmentRepoInterface, tasks repo.TaskRepoInterface, store storage.Storage, cnfg *configs.Config, logger *zap.Logger) service.AttachmentServiceInterface {
+	return &AttachmentService{
+		repo:       repo,
+		tasks:      tasks,
+		store:      store,
+		maxBytes:   cnfg.AttachmentMaxBytes,
+		quotaBytes: cnfg.AttachmentQuotaBytes,
+		logger:     logger,
+	}
+}
+
+// UploadAttachmentSvc stores a file of size bytes on a task of the user. The content type is
+// sniffed from the bytes rather than taken from the client, and the upload is refused when it
+// is over the per-file limit, of a type that is not accepted or over the user's quota.
+func (s *AttachmentService) UploadAttachmentSvc(ctx context.Context, taskID int, userID, filename string, size int64, body io.Reader) (*models.Attachment, error) {
+	if _, err := userTask(ctx, s.tasks, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: file is empty", globals.ErrValidation)
+	}
+	if size > s.maxBytes {
+		return nil, fmt.Errorf("%w: files can be at most %d bytes", globals.ErrAttachmentTooLarge, s.maxBytes)
+	}
+
+	used, err := s.repo.UsedBytes(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching used attachment storage from repo:", err)
+		return nil, err
+	}
+	if used+size > s.quotaBytes {
+		return nil, fmt.Errorf("%w: %d of %d bytes used", globals.ErrQuotaExceeded, used, s.quotaBytes)
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !allowedAttachmentTypes[mediaType] {
+		return nil, fmt.Errorf("%w: %s", globals.ErrUnsupportedMediaType, contentType)
+	}
+
+	task := int64(taskID)
+	attachment := &entity.Attachment{
+		TaskID:      &task,
+		UserID:      userID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  fmt.Sprintf("attachments/%d/%s", taskID, uuid.NewString()),
+	}
+
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), body), size)
+	if err := s.store.Put(ctx, attachment.StorageKey, content, size, contentType); err != nil {
+		log.Println("Error storing attachment:", err)
+		return nil, err
+	}
+
+	err = s.repo.CreateAttachment(ctx, attachment, s.quotaBytes)
+	if err != nil {
+		s.removeObject(ctx, attachment.StorageKey)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, globals.ErrQuotaExceeded
+		}
+		log.Println("Error creating attachment in repo:", err)
+		return nil, err
+	}
+	return toAttachmentModel(attachment), nil
+}
+
+// ListAttachmentsSvc returns the attachments of a task together with the storage used by the user
+func (s *AttachmentService) ListAttachmentsSvc(ctx context.Context, taskID int, userID string) (*models.AttachmentList, error) {
+	if _, err := userTask(ctx, s.tasks, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListAttachments(ctx, taskID)
+	if err != nil {
+		log.Println("Error fetching attachments from repo:", err)
+		return nil, err
+	}
+	used, err := s.repo.UsedBytes(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching used attachment storage from repo:", err)
+		return nil, err
+	}
+
+	result := make([]*models.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		result = append(result, toAttachmentModel(attachment))
+	}
+	return &models.AttachmentList{
+		Attachments: result,
+		UsedBytes:   used,
+		QuotaBytes:  s.quotaBytes,
+	}, nil
+}
+
+// DownloadAttachmentSvc returns an attachment and its content, the caller closes the reader
+func (s *AttachmentService) DownloadAttachmentSvc(ctx context.Context, taskID, attachmentID int, userID string) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.taskAttachment(ctx, taskID, attachmentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		log.Println("Attachment content missing from storage:", attachment.StorageKey)
+		return nil, nil, globals.ErrAttachmentNotFound
+	}
+	if err != nil {
+		log.Println("Error reading attachment from storage:", err)
+		return nil, nil, err
+	}
+	return toAttachmentModel(attachment), content, nil
+}
+
+// DeleteAttachmentSvc removes an attachment. The content goes first, so a storage failure keeps
+// the attachment around to retry instead of leaving unreferenced bytes behind.
+func (s *AttachmentService) DeleteAttachmentSvc(ctx context.Context, taskID, attachmentID int, userID string) error {
+	attachment, err := s.taskAttachment(ctx, taskID, attachmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		log.Println("Error deleting attachment from storage:", err)
+		return err
+	}
+	if err := s.repo.DeleteAttachment(ctx, attachmentID); err != nil {
+		log.Println("Error deleting attachment in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// SweepOrphanedAttachmentsSvc removes the content and rows of attachments whose task was
+// purged, it returns the number removed
+func (s *AttachmentService) SweepOrphanedAttachmentsSvc(ctx context.Context) (int64, error) {
+	attachments, err := s.repo.ListOrphanedAttachments(ctx, orphanSweepBatch)
+	if err != nil {
+		log.Println("Error fetching orphaned attachments from repo:", err)
+		return 0, err
+	}
+
+	var removed int64
+	for _, attachment := range attachments {
+		if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+			log.Println("Error deleting orphaned attachment from storage:", err)
+			continue
+		}
+		if err := s.repo.DeleteAttachment(ctx, int(attachment.ID)); err != nil {
+			log.Println("Error deleting orphaned attachment in repo:", err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// taskAttachment fetches an attachment of a task the user has access to
+func (s *AttachmentService) taskAttachment(ctx context.Context, taskID, attachmentID int, userID string) (*entity.Attachment, error) {
+	if _, err := userTask(ctx, s.tasks, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.repo.GetAttachmentByID(ctx, attachmentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrAttachmentNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching attachment from repo:", err)
+		return nil, err
+	}
+	if attachment.TaskID == nil || *attachment.TaskID != int64(taskID) {
+		return nil, globals.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// removeObject deletes stored content that ended up without an attachment row, a failure is
+// only logged
+func (s *AttachmentService) removeObject(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Println("Error deleting attachment from storage:", err)
+	}
+}
+
+// cleanFilename keeps the last element of an uploaded file name without control characters,
+// shortened to maxFilenameLength characters
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if utf8.RuneCountInString(name) > maxFilenameLength {
+		name = string([]rune(name)[:maxFilenameLength])
+	}
+	return name
+}
+
+func toAttachmentModel(attachment *entity.Attachment) *models.Attachment {
+	result := &models.Attachment{
+		ID:          attachment.ID,
+		UserID:      attachment.UserID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Created:     attachment.CreatedAt,
+	}
+	if attachment.TaskID != nil {
+		result.TodoID = *attachment.TaskID
+	}
+	return result
+}
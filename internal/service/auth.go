@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/jwt"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// RefreshTokenSvc exchanges a refresh token for a new token pair. The presented token is
+// used up, presenting it again revokes every token of its login since one of the two parties
+// holding it cannot be the user.
+func (s *UserService) RefreshTokenSvc(ctx context.Context, refreshToken string, client *models.Client) (*models.TokenPair, error) {
+	token, err := s.tokens.GetRefreshTokenByHash(ctx, jwt.HashOpaqueToken(refreshToken))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		log.Println("Error fetching refresh token from repo:", err)
+		return nil, err
+	}
+
+	if token.UsedAt != nil {
+		log.Printf("Refresh token reuse detected for user %s, revoking login %s", token.UserID, token.FamilyID)
+		if err := s.revokeFamily(ctx, token.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, globals.ErrRefreshTokenReused
+	}
+	if token.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, globals.ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return nil, err
+	}
+	tokens, err := s.issueTokens(ctx, user, token.FamilyID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	session := s.newSession(token.UserID, client)
+	session.ID = token.FamilyID
+	if err := s.sessions.TouchSession(ctx, session); err != nil {
+		log.Println("Error updating session in repo:", err)
+	}
+	return tokens, nil
+}
+
+// LogoutSvc revokes the access token with the given jti and every refresh token of the login
+// it was issued for
+func (s *UserService) LogoutSvc(ctx context.Context, userID, jti string, expiresAt time.Time) error {
+	if err := s.denyAccessToken(jti, expiresAt); err != nil {
+		return err
+	}
+
+	token, err := s.tokens.GetRefreshTokenByAccessJTI(ctx, jti)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		log.Println("Error fetching refresh token from repo:", err)
+		return err
+	}
+	if token.UserID != userID {
+		return nil
+	}
+	return s.revokeFamily(ctx, token.FamilyID)
+}
+
+// LogoutAllSvc revokes the refresh tokens of every login of the user along with the access
+// tokens still valid for them
+func (s *UserService) LogoutAllSvc(ctx context.Context, userID string) error {
+	revoked, err := s.tokens.RevokeUserTokens(ctx, userID)
+	if err != nil {
+		log.Println("Error revoking refresh tokens in repo:", err)
+		return err
+	}
+	if err := s.sessions.RevokeUserSessions(ctx, userID); err != nil {
+		log.Println("Error revoking sessions in repo:", err)
+		return err
+	}
+	return s.denyAccessTokens(revoked)
+}
+
+// issueTokens creates an access token and a refresh token of the given login family. With a
+// used token the new refresh token replaces it, otherwise it starts the family.
+func (s *UserService) issueTokens(ctx context.Context, user *entity.User, familyID string, used *entity.RefreshToken) (*models.TokenPair, error) {
+	accessTTL := time.Duration(s.cnfg.AccessTokenMinutes) * time.Minute
+	access, claims, err := jwt.GenerateToken(s.cnfg.SECRETKEY, user.Email, user.ID, accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, hash, err := jwt.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &entity.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hash,
+		AccessJTI:       claims.Id,
+		AccessExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		ExpiresAt:       time.Now().Add(time.Duration(s.cnfg.RefreshTokenDays) * 24 * time.Hour),
+	}
+
+	if used == nil {
+		if err := s.tokens.DeleteExpiredRefreshTokens(ctx, user.ID); err != nil {
+			log.Println("Error deleting expired refresh tokens in repo:", err)
+		}
+		err = s.tokens.CreateRefreshToken(ctx, record)
+	} else {
+		err = s.tokens.RotateRefreshToken(ctx, used.ID, record)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// a concurrent refresh used the token first
+			if err := s.revokeFamily(ctx, familyID); err != nil {
+				return nil, err
+			}
+			return nil, globals.ErrRefreshTokenReused
+		}
+	}
+	if err != nil {
+		log.Println("Error storing refresh token in repo:", err)
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTTL.Seconds()),
+	}, nil
+}
+
+// revokeFamily ends a session, revoking every refresh token of the login and the access tokens
+// issued with them
+func (s *UserService) revokeFamily(ctx context.Context, familyID string) error {
+	revoked, err := s.tokens.RevokeFamily(ctx, familyID)
+	if err != nil {
+		log.Println("Error revoking refresh tokens in repo:", err)
+		return err
+	}
+	if err := s.sessions.RevokeSession(ctx, familyID); err != nil {
+		log.Println("Error revoking session in repo:", err)
+		return err
+	}
+	return s.denyAccessTokens(revoked)
+}
+
+// denyAccessTokens denylists the access tokens issued with the given refresh tokens that have
+// not expired yet
+func (s *UserService) denyAccessTokens(tokens []*entity.RefreshToken) error {
+	for _, token := range tokens {
+		if err := s.denyAccessToken(token.AccessJTI, token.AccessExpiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// denyAccessToken makes the middleware reject an access token until it expires
+func (s *UserService) denyAccessToken(jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	if err := s.redis.SetDataInRedis(globals.TokenDenylistPrefix+jti, []byte("1"), ttl); err != nil {
+		log.Println("Error denylisting access token in redis:", err)
+		return err
+	}
+	return nil
+}
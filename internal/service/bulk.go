@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// bulkState is the view of the user's tasks while the operations of a bulk request are planned,
+// each planned operation is applied to it so later operations see its effect
+type bulkState struct {
+	tasks       map[int64]*entity.Task
+	children    map[int64][]int64
+	blockers    map[int64][]*entity.Dependency
+	statuses    []*entity.Status
+	transitions map[int64][]int64
+	tags        map[int64][]string // current tags of the referenced tasks, for their revisions
+	deleted     map[int64]bool
+	created     int64 // tasks created by the request are tracked under negative ids
+}
+
+// BulkTodosSvc applies a list of operations in one transaction. All referenced tasks are loaded
+// up front and the writes go to the database in a single batch. In atomic mode any failing
+// operation cancels the whole request, otherwise every operation succeeds or fails on its own.
+func (s *TaskService) BulkTodosSvc(ctx context.Context, userID string, req *models.BulkRequest) (*models.BulkResults, error) {
+	state, err := s.loadBulkState(ctx, userID, req.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*models.BulkResult, len(req.Operations))
+	ops := make([]*entity.TaskOp, len(req.Operations))
+	failed := false
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		results[i] = &models.BulkResult{Index: i, Op: op.Op, ID: op.ID}
+
+		taskOp, err := s.planBulkOp(ctx, userID, op, state)
+		if err != nil {
+			ops[i] = &entity.TaskOp{Err: err}
+			failed = true
+			continue
+		}
+		ops[i] = taskOp
+	}
+
+	if !(req.Atomic && failed) {
+		err := s.repo.ApplyTaskOps(ctx, userID, ops, req.Atomic)
+		if err != nil && !req.Atomic {
+			log.Println("Error applying bulk operations in repo:", err)
+			return nil, err
+		}
+		if err != nil {
+			failed = true
+			if !hasOpError(ops) {
+				log.Println("Error applying bulk operations in repo:", err)
+				return nil, err
+			}
+		}
+	}
+
+	summary := &models.BulkResults{Atomic: req.Atomic, Results: results}
+	var todos []*models.Todo
+	for i, taskOp := range ops {
+		result := results[i]
+		switch {
+		case taskOp.Err != nil:
+			result.Error = taskOp.Err.Error()
+			var transition *globals.TransitionError
+			if errors.As(taskOp.Err, &transition) {
+				result.Allowed = transition.Allowed
+			}
+		case req.Atomic && failed:
+			result.Error = globals.ErrBulkAborted.Error()
+		default:
+			result.Success = true
+			result.ID = taskOp.Task.ID
+			if taskOp.Kind != entity.TaskOpDelete {
+				result.Todo = toTodoModel(taskOp.Task)
+				todos = append(todos, result.Todo)
+			}
+		}
+
+		if result.Success {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	if err := s.attachTags(ctx, todos); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// loadBulkState fetches the tasks referenced by the operations together with all their subtasks
+func (s *TaskService) loadBulkState(ctx context.Context, userID string, operations []models.BulkOperation) (*bulkState, error) {
+	state := &bulkState{
+		tasks:    make(map[int64]*entity.Task),
+		children: make(map[int64][]int64),
+		deleted:  make(map[int64]bool),
+	}
+
+	var err error
+	if state.statuses, err = workflowStatuses(ctx, s.statuses, userID); err != nil {
+		return nil, err
+	}
+	if state.transitions, err = s.statuses.ListTransitions(ctx, userID); err != nil {
+		log.Println("Error fetching status transitions from repo:", err)
+		return nil, err
+	}
+
+	var ids []int64
+	for _, op := range operations {
+		if op.Op == models.BulkCreate {
+			if op.Todo.ParentID != nil {
+				ids = append(ids, *op.Todo.ParentID)
+			}
+			continue
+		}
+		ids = append(ids, op.ID)
+	}
+	if len(ids) == 0 {
+		return state, nil
+	}
+
+	tasks, err := s.repo.GetTodosByIDs(ctx, ids)
+	if err != nil {
+		log.Println("Error fetching todos from repo:", err)
+		return nil, err
+	}
+	subtasks, err := s.repo.ListSubtasks(ctx, userID, ids)
+	if err != nil {
+		log.Println("Error fetching subtasks from repo:", err)
+		return nil, err
+	}
+	if state.blockers, err = s.deps.ListBlockers(ctx, ids); err != nil {
+		log.Println("Error fetching todo blockers from repo:", err)
+		return nil, err
+	}
+	if state.tags, err = s.tags.ListTagsForTasks(ctx, ids); err != nil {
+		log.Println("Error fetching todo tags from repo:", err)
+		return nil, err
+	}
+
+	for _, task := range append(tasks, subtasks...) {
+		if _, ok := state.tasks[task.ID]; ok {
+			continue
+		}
+		state.tasks[task.ID] = task
+		if task.ParentID != nil {
+			state.children[*task.ParentID] = append(state.children[*task.ParentID], task.ID)
+		}
+	}
+	return state, nil
+}
+
+// owned returns the current state of a task of the user
+func (b *bulkState) owned(id int64, userID string) (*entity.Task, error) {
+	task, ok := b.tasks[id]
+	if !ok {
+		return nil, globals.ErrTaskNotFound
+	}
+	if task.UserID != userID {
+		return nil, globals.ErrTaskForbidden
+	}
+	return task, nil
+}
+
+// remove drops a deleted task and its subtasks
+func (b *bulkState) remove(id int64) {
+	delete(b.tasks, id)
+	b.deleted[id] = true
+	for _, child := range b.children[id] {
+		b.remove(child)
+	}
+}
+
+// openSubtasks counts the direct children of a task that are not completed
+func (b *bulkState) openSubtasks(id int64) int {
+	open := 0
+	for _, child := range b.children[id] {
+		if task, ok := b.tasks[child]; ok && !task.Terminal {
+			open++
+		}
+	}
+	return open
+}
+
+// openBlockers counts the blockers of a task that are not done, taking earlier operations into account
+func (b *bulkState) openBlockers(id int64) int {
+	open := 0
+	for _, dep := range b.blockers[id] {
+		done := dep.BlockerDone
+		if task, ok := b.tasks[dep.BlockedByID]; ok {
+			done = task.Terminal
+		} else if b.deleted[dep.BlockedByID] {
+			continue
+		}
+		if !done {
+			open++
+		}
+	}
+	return open
+}
+
+// planBulkOp checks one operation against the current state and turns it into the write to run.
+// Completing an instance of a repeating task also writes the next instance.
+func (s *TaskService) planBulkOp(ctx context.Context, userID string, op *models.BulkOperation, state *bulkState) (taskOp *entity.TaskOp, err error) {
+	if op.Op == models.BulkCreate {
+		todo := *op.Todo
+		todo.UserID = userID
+
+		var parent *entity.Task
+		if todo.ParentID != nil {
+			if parent, err = state.owned(*todo.ParentID, userID); err != nil {
+				return nil, fmt.Errorf("parent %w", globals.ErrTaskNotFound)
+			}
+		}
+
+		task, tags, err := s.newTask(ctx, &todo, parent, state.statuses)
+		if err != nil {
+			return nil, err
+		}
+		if parent != nil {
+			// so that completing the parent later in the request sees the new subtask
+			state.created--
+			state.tasks[state.created] = task
+			state.children[parent.ID] = append(state.children[parent.ID], state.created)
+		}
+		revision := newRevision(entity.RevisionCreate, userID, nil, snapshotTask(task, tags, false))
+		return &entity.TaskOp{Kind: entity.TaskOpCreate, Task: task, Tags: tags, SetTags: len(tags) > 0, Revision: revision}, nil
+	}
+
+	existing, err := state.owned(op.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(existing, op.Version); err != nil {
+		return nil, err
+	}
+
+	if op.Op == models.BulkDelete {
+		task := *existing
+		state.remove(op.ID)
+		revision := newRevision(entity.RevisionDelete, userID,
+			snapshotTask(&task, state.tags[op.ID], false), snapshotTask(&task, state.tags[op.ID], true))
+		return &entity.TaskOp{Kind: entity.TaskOpDelete, Task: &task, Revision: revision}, nil
+	}
+
+	updated := existing
+	var tags []string
+	switch op.Op {
+	case models.BulkUpdate:
+		if updated, tags, err = applyPatch(existing, op.Patch, state.statuses); err != nil {
+			return nil, err
+		}
+	case models.BulkComplete:
+		task := *existing
+		setStatus(&task, doneStatus(state.statuses))
+		updated = &task
+	case models.BulkMove:
+		task := *existing
+		if op.ProjectID != nil {
+			task.ProjectID = op.ProjectID
+		} else {
+			inbox, err := defaultProject(ctx, s.projects, userID)
+			if err != nil {
+				return nil, err
+			}
+			task.ProjectID = &inbox.ID
+		}
+		updated = &task
+	}
+
+	// the same rules as a single update
+	if err := checkTransition(state.statuses, state.transitions, existing, updated); err != nil {
+		return nil, err
+	}
+	if updated.ProjectID != nil && (existing.ProjectID == nil || *updated.ProjectID != *existing.ProjectID) {
+		if _, err := ownedProject(ctx, s.projects, int(*updated.ProjectID), userID); err != nil {
+			return nil, err
+		}
+	}
+	if updated.Terminal {
+		if open := state.openSubtasks(op.ID); open > 0 {
+			return nil, fmt.Errorf("task has %d subtasks that are not completed", open)
+		}
+		if pending := state.openBlockers(op.ID); pending > 0 && !existing.Terminal && !op.Force {
+			return nil, fmt.Errorf("%w: %d blockers are not completed", globals.ErrTaskBlocked, pending)
+		}
+	}
+
+	setTags := op.Op == models.BulkUpdate && op.Patch.Tags.Set
+	oldTags, newTags := state.tags[op.ID], state.tags[op.ID]
+	if setTags {
+		newTags = tags
+		state.tags[op.ID] = tags
+	}
+
+	var occurrence *entity.TaskOp
+	if existing.Recurrence != "" && !existing.Terminal && updated.Terminal {
+		if occurrence, err = nextOccurrence(updated, state.statuses, newTags); err != nil {
+			return nil, err
+		}
+		updated.Recurrence = ""
+	}
+
+	// later operations on the task expect the version this one will leave behind
+	next := *updated
+	next.Version++
+	state.tasks[op.ID] = &next
+
+	revision := newRevision(entity.RevisionUpdate, userID,
+		snapshotTask(existing, oldTags, false), snapshotTask(updated, newTags, false))
+	return &entity.TaskOp{Kind: entity.TaskOpUpdate, Task: updated, Tags: tags, SetTags: setTags, Revision: revision, Then: occurrence}, nil
+}
+
+// hasOpError reports whether the failure of a bulk request is tied to one of its operations
+func hasOpError(ops []*entity.TaskOp) bool {
+	for _, op := range ops {
+		if op.Err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+const (
+	// maxChecklistItems limits the number of items in the checklist of a task
+	maxChecklistItems = 100
+	// maxChecklistItemLength limits the number of characters of an item
+	maxChecklistItemLength = 500
+)
+
+// AddChecklistItemSvc adds an unchecked item at the end of the checklist of a task of the user
+func (s *TaskService) AddChecklistItemSvc(ctx context.Context, taskID int, userID, text string) (*models.Checklist, error) {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	text, err := normalizeChecklistText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.checklist.ListItems(ctx, taskID)
+	if err != nil {
+		log.Println("Error fetching checklist items from repo:", err)
+		return nil, err
+	}
+	if len(items) >= maxChecklistItems {
+		return nil, fmt.Errorf("%w: a checklist can have at most %d items", globals.ErrValidation, maxChecklistItems)
+	}
+
+	item := &entity.ChecklistItem{TaskID: int64(taskID), Text: text}
+	if err := s.checklist.CreateItem(ctx, item); err != nil {
+		log.Println("Error creating checklist item in repo:", err)
+		return nil, err
+	}
+	return toChecklistModel(int64(taskID), append(items, item)), nil
+}
+
+// UpdateChecklistItemSvc changes the text of an item and checks or unchecks it. When the
+// last open item is checked and auto-completion is enabled the task is completed as well,
+// unless the workflow, pending blockers or open subtasks prevent it.
+func (s *TaskService) UpdateChecklistItemSvc(ctx context.Context, taskID, itemID int, userID string, patch *models.ChecklistItemPatch) (*models.Checklist, error) {
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.checklistItem(ctx, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := false
+	if patch.Text != nil {
+		if item.Text, err = normalizeChecklistText(*patch.Text); err != nil {
+			return nil, err
+		}
+	}
+	if patch.Done != nil {
+		checked = *patch.Done && !item.Done
+		item.Done = *patch.Done
+	}
+
+	if err := s.checklist.UpdateItem(ctx, item); err != nil {
+		log.Println("Error updating checklist item in repo:", err)
+		return nil, err
+	}
+
+	items, err := s.checklist.ListItems(ctx, taskID)
+	if err != nil {
+		log.Println("Error fetching checklist items from repo:", err)
+		return nil, err
+	}
+	checklist := toChecklistModel(int64(taskID), items)
+
+	if checked && s.autoComplete && !task.Terminal && checklist.Progress.Done == checklist.Progress.Total {
+		checklist.TaskCompleted = s.completeTask(ctx, task, userID)
+	}
+	return checklist, nil
+}
+
+// ReorderChecklistSvc puts the items of a checklist in the given order, every item has to be
+// listed exactly once
+func (s *TaskService) ReorderChecklistSvc(ctx context.Context, taskID int, userID string, itemIDs []int64) (*models.Checklist, error) {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.checklist.ListItems(ctx, taskID)
+	if err != nil {
+		log.Println("Error fetching checklist items from repo:", err)
+		return nil, err
+	}
+
+	ordered, err := orderChecklist(items, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checklist.ReorderItems(ctx, taskID, itemIDs); err != nil {
+		log.Println("Error reordering checklist items in repo:", err)
+		return nil, err
+	}
+	return toChecklistModel(int64(taskID), ordered), nil
+}
+
+// orderChecklist returns the items in the order of itemIDs with their new positions. itemIDs
+// must list every item exactly once.
+func orderChecklist(items []*entity.ChecklistItem, itemIDs []int64) ([]*entity.ChecklistItem, error) {
+	byID := make(map[int64]*entity.ChecklistItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	if len(itemIDs) != len(items) {
+		return nil, fmt.Errorf("%w: the new order must list all %d items of the checklist", globals.ErrValidation, len(items))
+	}
+	ordered := make([]*entity.ChecklistItem, 0, len(items))
+	for i, id := range itemIDs {
+		item, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: item %d is missing, repeated or not on this checklist", globals.ErrValidation, id)
+		}
+		delete(byID, id)
+		item.Position = i + 1
+		ordered = append(ordered, item)
+	}
+	return ordered, nil
+}
+
+// DeleteChecklistItemSvc removes an item from the checklist of a task of the user
+func (s *TaskService) DeleteChecklistItemSvc(ctx context.Context, taskID, itemID int, userID string) error {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return err
+	}
+	if _, err := s.checklistItem(ctx, taskID, itemID); err != nil {
+		return err
+	}
+
+	if err := s.checklist.DeleteItem(ctx, itemID); err != nil {
+		log.Println("Error deleting checklist item in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// checklistItem fetches an item and makes sure it belongs to the checklist of the task
+func (s *TaskService) checklistItem(ctx context.Context, taskID, itemID int) (*entity.ChecklistItem, error) {
+	item, err := s.checklist.GetItemByID(ctx, itemID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrChecklistItemNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching checklist item from repo:", err)
+		return nil, err
+	}
+	if item.TaskID != int64(taskID) {
+		return nil, globals.ErrChecklistItemNotFound
+	}
+	return item, nil
+}
+
+// checklistItems returns the checklist of a task as models
+func (s *TaskService) checklistItems(ctx context.Context, taskID int) ([]*models.ChecklistItem, error) {
+	items, err := s.checklist.ListItems(ctx, taskID)
+	if err != nil {
+		log.Println("Error fetching checklist items from repo:", err)
+		return nil, err
+	}
+	return toChecklistModel(int64(taskID), items).Items, nil
+}
+
+// completeTask moves a task to the done status of the workflow through the regular update
+// checks. The checklist change that triggered it is already saved, so a task that cannot be
+// completed is only logged. It reports whether the task was completed.
+func (s *TaskService) completeTask(ctx context.Context, task *entity.Task, userID string) bool {
+	statuses, err := workflowStatuses(ctx, s.statuses, userID)
+	if err != nil {
+		return false
+	}
+
+	updated := *task
+	setStatus(&updated, doneStatus(statuses))
+	revision := &entity.Revision{Action: entity.RevisionUpdate, ActorID: userID}
+	if err := s.saveTask(ctx, task, &updated, nil, false, false, revision); err != nil {
+		log.Println("Checklist is done but the todo could not be completed:", err)
+		return false
+	}
+	return true
+}
+
+// normalizeChecklistText trims the text of an item and checks that it is neither empty nor too long
+func normalizeChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("%w: checklist item cannot be empty", globals.ErrValidation)
+	}
+	if utf8.RuneCountInString(text) > maxChecklistItemLength {
+		return "", fmt.Errorf("%w: checklist item is longer than %d characters", globals.ErrValidation, maxChecklistItemLength)
+	}
+	return text, nil
+}
+
+func toChecklistModel(taskID int64, items []*entity.ChecklistItem) *models.Checklist {
+	checklist := &models.Checklist{
+		TodoID: taskID,
+		Items:  make([]*models.ChecklistItem, 0, len(items)),
+	}
+	for _, item := range items {
+		checklist.Items = append(checklist.Items, &models.ChecklistItem{
+			ID:       item.ID,
+			TodoID:   item.TaskID,
+			Text:     item.Text,
+			Done:     item.Done,
+			Position: item.Position,
+			Created:  item.CreatedAt,
+			Updated:  item.UpdatedAt,
+		})
+		if item.Done {
+			checklist.Progress.Done++
+		}
+	}
+	checklist.Progress.Total = len(items)
+	return checklist
+}
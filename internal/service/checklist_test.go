@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+func checklistFixture() []*entity.ChecklistItem {
+	return []*entity.ChecklistItem{
+		{ID: 10, TaskID: 1, Text: "eggs", Position: 1},
+		{ID: 11, TaskID: 1, Text: "milk", Position: 2},
+		{ID: 12, TaskID: 1, Text: "bread", Position: 3},
+	}
+}
+
+func TestOrderChecklist(t *testing.T) {
+	tests := []struct {
+		name    string
+		itemIDs []int64
+	}{
+		{"reversed", []int64{12, 11, 10}},
+		{"unchanged", []int64{10, 11, 12}},
+		{"moved to front", []int64{11, 10, 12}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := orderChecklist(checklistFixture(), tt.itemIDs)
+			if err != nil {
+				t.Fatalf("orderChecklist error: %v", err)
+			}
+			if len(ordered) != len(tt.itemIDs) {
+				t.Fatalf("orderChecklist returned %d items, want %d", len(ordered), len(tt.itemIDs))
+			}
+			for i, item := range ordered {
+				if item.ID != tt.itemIDs[i] || item.Position != i+1 {
+					t.Errorf("item %d = id %d at %d, want id %d at %d", i, item.ID, item.Position, tt.itemIDs[i], i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderChecklistRejectsInvalidOrders(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []*entity.ChecklistItem
+		itemIDs []int64
+	}{
+		{"item missing", checklistFixture(), []int64{12, 11}},
+		{"item repeated", checklistFixture(), []int64{12, 12, 10}},
+		{"foreign item", checklistFixture(), []int64{12, 11, 99}},
+		{"extra item", checklistFixture(), []int64{12, 11, 10, 99}},
+		{"empty order", checklistFixture(), nil},
+		{"order for an empty checklist", nil, []int64{10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := orderChecklist(tt.items, tt.itemIDs); !errors.Is(err, globals.ErrValidation) {
+				t.Errorf("orderChecklist(%v) error = %v, want ErrValidation", tt.itemIDs, err)
+			}
+		})
+	}
+
+	if ordered, err := orderChecklist(nil, nil); err != nil || len(ordered) != 0 {
+		t.Errorf("orderChecklist of an empty checklist = %v, %v, want no items", ordered, err)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	repo "github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+	service "github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+	"go.uber.org/zap"
+)
+
+// maxCommentLength limits the number of characters in a comment
+const maxCommentLength = 10000
+
+type CommentService struct {
+	repo   repo.CommentRepoInterface
+	tasks  repo.TaskRepoInterface
+	logger *zap.Logger
+}
+
+func NewCommentService(repo repo.CommentRepoInterface, tasks repo.TaskRepoInterface, logger *zap.Logger) service.CommentServiceInterface {
+	return &CommentService{
+		repo:   repo,
+		tasks:  tasks,
+		logger: logger,
+	}
+}
+
+// AddCommentSvc adds a comment to a task the user has access to
+func (s *CommentService) AddCommentSvc(ctx context.Context, comment *models.Comment) error {
+	if _, err := userTask(ctx, s.tasks, int(comment.TodoID), comment.UserID); err != nil {
+		return err
+	}
+
+	body, err := normalizeComment(comment.Body)
+	if err != nil {
+		return err
+	}
+
+	entityComment := &entity.Comment{
+		TaskID: comment.TodoID,
+		UserID: comment.UserID,
+		Body:   body,
+	}
+	if err := s.repo.CreateComment(ctx, entityComment); err != nil {
+		log.Println("Error creating comment in repo:", err)
+		return err
+	}
+
+	*comment = *toCommentModel(entityComment)
+	return nil
+}
+
+// ListCommentsSvc returns a page of the comments of a task, oldest first
+func (s *CommentService) ListCommentsSvc(ctx context.Context, taskID int, userID string, limit, offset int) (*models.PaginatedComments, error) {
+	if _, err := userTask(ctx, s.tasks, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	comments, total, err := s.repo.ListComments(ctx, taskID, limit, offset)
+	if err != nil {
+		log.Println("Error fetching comments from repo:", err)
+		return nil, err
+	}
+
+	result := make([]*models.Comment, 0, len(comments))
+	for _, comment := range comments {
+		result = append(result, toCommentModel(comment))
+	}
+	return &models.PaginatedComments{
+		TotalCount: total,
+		Comments:   result,
+	}, nil
+}
+
+// UpdateCommentSvc replaces the text of a comment, only its author can edit it
+func (s *CommentService) UpdateCommentSvc(ctx context.Context, taskID, commentID int, userID, body string) (*models.Comment, error) {
+	comment, err := s.authoredComment(ctx, taskID, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.Body, err = normalizeComment(body); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateComment(ctx, comment); err != nil {
+		log.Println("Error updating comment in repo:", err)
+		return nil, err
+	}
+	return toCommentModel(comment), nil
+}
+
+// DeleteCommentSvc removes a comment, only its author can delete it
+func (s *CommentService) DeleteCommentSvc(ctx context.Context, taskID, commentID int, userID string) error {
+	if _, err := s.authoredComment(ctx, taskID, commentID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteComment(ctx, commentID); err != nil {
+		log.Println("Error deleting comment in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// authoredComment fetches a comment of a task the user has access to and makes sure the user wrote it
+func (s *CommentService) authoredComment(ctx context.Context, taskID, commentID int, userID string) (*entity.Comment, error) {
+	if _, err := userTask(ctx, s.tasks, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrCommentNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching comment from repo:", err)
+		return nil, err
+	}
+	if comment.TaskID != int64(taskID) {
+		return nil, globals.ErrCommentNotFound
+	}
+	if comment.UserID != userID {
+		return nil, globals.ErrCommentForbidden
+	}
+	return comment, nil
+}
+
+// normalizeComment trims a comment and checks that it is neither empty nor too long
+func normalizeComment(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: comment cannot be empty", globals.ErrValidation)
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("%w: comment is longer than %d characters", globals.ErrValidation, maxCommentLength)
+	}
+	return body, nil
+}
+
+func toCommentModel(comment *entity.Comment) *models.Comment {
+	return &models.Comment{
+		ID:      comment.ID,
+		TodoID:  comment.TaskID,
+		UserID:  comment.UserID,
+		Body:    comment.Body,
+		Created: comment.CreatedAt,
+		Updated: comment.UpdatedAt,
+	}
+}
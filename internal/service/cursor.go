@@ -0,0 +1,85 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// cursorToken is the payload of the opaque cursors handed out by the list endpoint. It carries
+// the sort values of a row and the sort it was created for, so it cannot be replayed against
+// a different order.
+type cursorToken struct {
+	Sort      string     `json:"s"`
+	ID        int64      `json:"i"`
+	DueAt     *time.Time `json:"d,omitempty"`
+	Priority  int        `json:"p,omitempty"`
+	Title     string     `json:"t,omitempty"`
+	CreatedAt time.Time  `json:"c"`
+	UpdatedAt time.Time  `json:"u"`
+	Backward  bool       `json:"b,omitempty"`
+}
+
+// sortSignature identifies a sort order inside a cursor
+func sortSignature(keys []entity.SortKey) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.Desc {
+			parts = append(parts, "-"+key.Field)
+		} else {
+			parts = append(parts, key.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor builds the cursor pointing after (or before when backward) the given task
+func encodeCursor(task *entity.Task, keys []entity.SortKey, backward bool) string {
+	token := cursorToken{
+		Sort:      sortSignature(keys),
+		ID:        task.ID,
+		Priority:  task.Priority,
+		Title:     task.Title,
+		CreatedAt: task.CreatedAt,
+		UpdatedAt: task.UpdatedAt,
+		Backward:  backward,
+	}
+	if !task.DueAt.IsZero() {
+		dueAt := task.DueAt
+		token.DueAt = &dueAt
+	}
+
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor reads a cursor and checks that it was issued for the same sort order
+func decodeCursor(cursor string, keys []entity.SortKey) (*entity.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", globals.ErrValidation)
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", globals.ErrValidation)
+	}
+	if token.Sort != sortSignature(keys) {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", globals.ErrValidation)
+	}
+
+	return &entity.Cursor{
+		ID:        token.ID,
+		DueAt:     token.DueAt,
+		Priority:  token.Priority,
+		Title:     token.Title,
+		CreatedAt: token.CreatedAt,
+		UpdatedAt: token.UpdatedAt,
+		Backward:  token.Backward,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.UTC)
+	updated := created.Add(time.Hour)
+	due := time.Date(2024, 3, 8, 9, 0, 0, 0, time.FixedZone("IST", 5*3600+1800))
+
+	tests := []struct {
+		name     string
+		task     *entity.Task
+		keys     []entity.SortKey
+		backward bool
+	}{
+		{
+			name: "default sort",
+			task: &entity.Task{ID: 42, Title: "Buy milk", Priority: 2, CreatedAt: created, UpdatedAt: updated},
+		},
+		{
+			name:     "due date backward",
+			task:     &entity.Task{ID: 7, Title: "Pay rent", Priority: 3, DueAt: due, CreatedAt: created, UpdatedAt: updated},
+			keys:     []entity.SortKey{{Field: "due_at"}, {Field: "priority", Desc: true}},
+			backward: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeCursor(encodeCursor(tt.task, tt.keys, tt.backward), tt.keys)
+			if err != nil {
+				t.Fatalf("decodeCursor error: %v", err)
+			}
+
+			if cursor.ID != tt.task.ID || cursor.Title != tt.task.Title || cursor.Priority != tt.task.Priority {
+				t.Errorf("cursor = %+v, want the values of %+v", cursor, tt.task)
+			}
+			if !cursor.CreatedAt.Equal(tt.task.CreatedAt) || !cursor.UpdatedAt.Equal(tt.task.UpdatedAt) {
+				t.Errorf("cursor times = %s, %s, want %s, %s", cursor.CreatedAt, cursor.UpdatedAt, tt.task.CreatedAt, tt.task.UpdatedAt)
+			}
+			if tt.task.DueAt.IsZero() {
+				if cursor.DueAt != nil {
+					t.Errorf("cursor due date = %s, want none", cursor.DueAt)
+				}
+			} else if cursor.DueAt == nil || !cursor.DueAt.Equal(tt.task.DueAt) {
+				t.Errorf("cursor due date = %v, want %s", cursor.DueAt, tt.task.DueAt)
+			}
+			if cursor.Backward != tt.backward {
+				t.Errorf("cursor backward = %v, want %v", cursor.Backward, tt.backward)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	task := &entity.Task{ID: 42, Title: "Buy milk", CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	keys := []entity.SortKey{{Field: "priority", Desc: true}, {Field: "title"}}
+	valid := encodeCursor(task, keys, false)
+
+	// swap the sort recorded in the cursor while keeping it well-formed
+	data, _ := base64.RawURLEncoding.DecodeString(valid)
+	resorted := base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(data), `"s":"-priority,title"`, `"s":"title"`, 1)))
+	if resorted == valid {
+		t.Fatalf("cursor %s does not record its sort", data)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		keys   []entity.SortKey
+	}{
+		{"not base64", "not a cursor!", keys},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("{broken")), keys},
+		{"wrong field type", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"title","i":"42"}`)), []entity.SortKey{{Field: "title"}}},
+		{"truncated", valid[:len(valid)/2], keys},
+		{"different sort", valid, []entity.SortKey{{Field: "title"}}},
+		{"different direction", valid, []entity.SortKey{{Field: "priority"}, {Field: "title"}}},
+		{"sort rewritten", resorted, keys},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(tt.cursor, tt.keys)
+			if !errors.Is(err, globals.ErrValidation) {
+				t.Errorf("decodeCursor error = %v, want ErrValidation", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// AddDependencySvc marks a task as blocked by another task, both must belong to the user
+func (s *TaskService) AddDependencySvc(ctx context.Context, taskID, blockerID int, userID string) error {
+	if taskID == blockerID {
+		return fmt.Errorf("%w: a task cannot depend on itself", globals.ErrValidation)
+	}
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return err
+	}
+	if _, err := s.ownedTask(ctx, blockerID, userID); err != nil {
+		return err
+	}
+
+	if err := s.deps.AddDependency(ctx, int64(taskID), int64(blockerID)); err != nil {
+		log.Println("Error adding todo dependency in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveDependencySvc deletes a blocked-by relationship of one of the user's tasks
+func (s *TaskService) RemoveDependencySvc(ctx context.Context, taskID, blockerID int, userID string) error {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return err
+	}
+
+	removed, err := s.deps.RemoveDependency(ctx, int64(taskID), int64(blockerID))
+	if err != nil {
+		log.Println("Error removing todo dependency in repo:", err)
+		return err
+	}
+	if !removed {
+		return globals.ErrDependencyNotFound
+	}
+	return nil
+}
+
+// openBlockers counts the blockers that have not reached a terminal status
+func openBlockers(blockers []*entity.Dependency) int {
+	open := 0
+	for _, dep := range blockers {
+		if !dep.BlockerDone {
+			open++
+		}
+	}
+	return open
+}
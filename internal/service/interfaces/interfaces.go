@@ -2,19 +2,90 @@ package interfaces
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/shivarajshanthaiah/todo-app/internal/models"
 )
 
 type TaskServiceInterface interface {
 	CreateTodoSvc(ctx context.Context, todo *models.Todo) error
-	GetTodoByUserIDSvc(ctx context.Context, userID, status string, limit, offset int) (*models.PaginatedTodos, error)
-	UpdateTodoByIDSvc(ctx context.Context, todo *models.Todo) error
-	DeleteTodoByIDSvc(ctx context.Context, taskID int, userID string) error
+	GetTodoByUserIDSvc(ctx context.Context, userID string, req *models.Request) (*models.PaginatedTodos, error)
+	GetTodoByIDSvc(ctx context.Context, taskID int, userID string) (*models.Todo, error)
+	BulkTodosSvc(ctx context.Context, userID string, req *models.BulkRequest) (*models.BulkResults, error)
+	SearchTodosSvc(ctx context.Context, userID string, req *models.SearchRequest) (*models.SearchResults, error)
+	UpdateTodoByIDSvc(ctx context.Context, todo *models.Todo, force bool) error
+	PatchTodoSvc(ctx context.Context, taskID int, userID string, ifMatch int64, force bool, patch *models.TodoPatch) (*models.Todo, error)
+	GetStatusHistorySvc(ctx context.Context, taskID int, userID string) (*models.StatusHistory, error)
+	GetTodoHistorySvc(ctx context.Context, taskID int, userID string, limit, offset int) (*models.PaginatedRevisions, error)
+	RevertTodoSvc(ctx context.Context, taskID, revision int, userID string, ifMatch int64, force bool) (*models.Todo, error)
+	DeleteTodoByIDSvc(ctx context.Context, taskID int, userID string, ifMatch int64) error
+	AddDependencySvc(ctx context.Context, taskID, blockerID int, userID string) error
+	RemoveDependencySvc(ctx context.Context, taskID, blockerID int, userID string) error
+	AddChecklistItemSvc(ctx context.Context, taskID int, userID, text string) (*models.Checklist, error)
+	UpdateChecklistItemSvc(ctx context.Context, taskID, itemID int, userID string, patch *models.ChecklistItemPatch) (*models.Checklist, error)
+	ReorderChecklistSvc(ctx context.Context, taskID int, userID string, itemIDs []int64) (*models.Checklist, error)
+	DeleteChecklistItemSvc(ctx context.Context, taskID, itemID int, userID string) error
+	UpdateRecurrenceSvc(ctx context.Context, taskID int, userID, rule string, ifMatch int64) error
+	ListTrashSvc(ctx context.Context, userID string, limit, offset int) (*models.PaginatedTodos, error)
+	RestoreTodoSvc(ctx context.Context, taskID int, userID string) error
+	PurgeTodoSvc(ctx context.Context, taskID int, userID string) error
+	PurgeExpiredTrashSvc(ctx context.Context, retention time.Duration) (int64, error)
 }
 
 type UserServiceInterface interface {
 	UserSignUpSvc(ctx context.Context, user *models.User) error
-	UserLoginSvc(ctx context.Context, login *models.Login) (string, error)
+	UserLoginSvc(ctx context.Context, login *models.Login, client *models.Client) (*models.TokenPair, *models.LoginChallenge, error)
+	LoginTwoFactorSvc(ctx context.Context, req *models.TwoFactorLogin, client *models.Client) (*models.TokenPair, error)
+	EnrollTwoFactorSvc(ctx context.Context, userID string) (*models.TwoFactorEnrollment, error)
+	ConfirmTwoFactorSvc(ctx context.Context, userID, code string) (*models.RecoveryCodes, error)
+	DisableTwoFactorSvc(ctx context.Context, userID, password string) error
+	RefreshTokenSvc(ctx context.Context, refreshToken string, client *models.Client) (*models.TokenPair, error)
+	LogoutSvc(ctx context.Context, userID, jti string, expiresAt time.Time) error
+	LogoutAllSvc(ctx context.Context, userID string) error
+	ForgotPasswordSvc(ctx context.Context, email string) error
+	ResetPasswordSvc(ctx context.Context, token, password string) error
+	VerifyEmailSvc(ctx context.Context, token string) error
+	ResendVerificationSvc(ctx context.Context, email string) error
+	EnsureEmailVerifiedSvc(ctx context.Context, userID string) error
+	ListSessionsSvc(ctx context.Context, userID, currentJTI string) ([]*models.Session, error)
+	RevokeSessionSvc(ctx context.Context, userID, sessionID string) error
+	ListLoginHistorySvc(ctx context.Context, userID string, limit, offset int) (*models.PaginatedLoginAttempts, error)
 	GetUserByIDSvc(ctx context.Context, userID string) (*models.User, string, error)
 }
+
+type ProjectServiceInterface interface {
+	CreateProjectSvc(ctx context.Context, project *models.Project) error
+	ListProjectsSvc(ctx context.Context, userID string) ([]*models.Project, error)
+	UpdateProjectSvc(ctx context.Context, project *models.Project) error
+	DeleteProjectSvc(ctx context.Context, projectID int, userID string, opts *models.DeleteProject) error
+}
+
+type StatusServiceInterface interface {
+	ListStatusesSvc(ctx context.Context, userID string) ([]*models.Status, error)
+	CreateStatusSvc(ctx context.Context, status *models.Status) error
+	UpdateStatusSvc(ctx context.Context, statusID int, userID string, patch *models.StatusPatch) (*models.Status, error)
+	DeleteStatusSvc(ctx context.Context, statusID int, userID string, opts *models.DeleteStatus) error
+	SetTransitionsSvc(ctx context.Context, statusID int, userID string, req *models.Transitions) (*models.Status, error)
+}
+
+type AttachmentServiceInterface interface {
+	UploadAttachmentSvc(ctx context.Context, taskID int, userID, filename string, size int64, body io.Reader) (*models.Attachment, error)
+	ListAttachmentsSvc(ctx context.Context, taskID int, userID string) (*models.AttachmentList, error)
+	DownloadAttachmentSvc(ctx context.Context, taskID, attachmentID int, userID string) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachmentSvc(ctx context.Context, taskID, attachmentID int, userID string) error
+	SweepOrphanedAttachmentsSvc(ctx context.Context) (int64, error)
+}
+
+type CommentServiceInterface interface {
+	AddCommentSvc(ctx context.Context, comment *models.Comment) error
+	ListCommentsSvc(ctx context.Context, taskID int, userID string, limit, offset int) (*models.PaginatedComments, error)
+	UpdateCommentSvc(ctx context.Context, taskID, commentID int, userID, body string) (*models.Comment, error)
+	DeleteCommentSvc(ctx context.Context, taskID, commentID int, userID string) error
+}
+
+type TagServiceInterface interface {
+	ListTagsSvc(ctx context.Context, userID string) ([]*models.Tag, error)
+	RenameTagSvc(ctx context.Context, tagID int, userID, name string) error
+	MergeTagsSvc(ctx context.Context, sourceID, targetID int, userID string) error
+}
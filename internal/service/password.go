@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/clients/mailer"
+	"github.com/shivarajshanthaiah/todo-app/internal/jwt"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+const (
+	// minPasswordLength is the number of characters a new password needs at least
+	minPasswordLength = 8
+	// mailTimeout bounds sending an email in the background
+	mailTimeout = 30 * time.Second
+)
+
+// ForgotPasswordSvc mails a reset token to the user with the given email. It succeeds for
+// unknown emails too and sends the mail in the background, so neither the response nor its
+// timing tells whether an account exists. Requests for the same email are throttled the same
+// way whether or not it has an account.
+func (s *UserService) ForgotPasswordSvc(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	allowed, err := s.redis.SetIfAbsentInRedis(globals.PasswordResetResendPrefix+strings.ToLower(email), []byte("1"), s.resendCooldown())
+	if err != nil {
+		log.Println("Error throttling password reset email in redis:", err)
+		return err
+	}
+	if !allowed {
+		return globals.ErrTooManyRequests
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return err
+	}
+
+	token, hash, err := jwt.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(s.cnfg.PasswordResetMinutes) * time.Minute
+	if err := s.tokens.CreatePasswordReset(ctx, user.ID, hash, time.Now().Add(ttl)); err != nil {
+		log.Println("Error creating password reset in repo:", err)
+		return err
+	}
+
+	body := fmt.Sprintf("Someone asked to reset the password of your account.\n\n"+
+		"Use this code to choose a new password within %d minutes:\n\n%s\n", s.cnfg.PasswordResetMinutes, token)
+	if s.cnfg.AppURL != "" {
+		body += fmt.Sprintf("\nOr open %s/reset-password?token=%s\n", strings.TrimSuffix(s.cnfg.AppURL, "/"), url.QueryEscape(token))
+	}
+	body += "\nIf you did not ask for this you can ignore this email, your password stays the same.\n"
+
+	s.sendMail(&mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    body,
+	})
+	return nil
+}
+
+// ResetPasswordSvc sets a new password with a reset token. The token works once, and every
+// session of the user is logged out since whoever knew the old password may be holding one.
+func (s *UserService) ResetPasswordSvc(ctx context.Context, token, password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return fmt.Errorf("%w: password needs at least %d characters", globals.ErrValidation, minPasswordLength)
+	}
+
+	userID, err := s.tokens.ConsumePasswordReset(ctx, jwt.HashOpaqueToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return globals.ErrInvalidResetToken
+	}
+	if err != nil {
+		log.Println("Error consuming password reset in repo:", err)
+		return err
+	}
+
+	hashedPassword, err := jwt.HashPassword(password)
+	if err != nil {
+		log.Println("Error while hashing the password", err)
+		return err
+	}
+	if err := s.repo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		log.Println("Error updating password in repo:", err)
+		return err
+	}
+	return s.LogoutAllSvc(ctx, userID)
+}
+
+// sendMail sends an email in the background, a failure is only logged
+func (s *UserService) sendMail(msg *mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancel()
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("Error sending mail to %s: %v", msg.To, err)
+		}
+	}()
+}
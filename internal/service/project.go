@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	repo "github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+	service "github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+	"go.uber.org/zap"
+)
+
+type ProjectService struct {
+	repo   repo.ProjectRepoInterface
+	logger *zap.Logger
+}
+
+func NewProjectService(repo repo.ProjectRepoInterface, logger *zap.Logger) service.ProjectServiceInterface {
+	return &ProjectService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *ProjectService) CreateProjectSvc(ctx context.Context, project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return fmt.Errorf("%w: project name is required", globals.ErrValidation)
+	}
+
+	entityProject := &entity.Project{
+		UserID: project.UserID,
+		Name:   project.Name,
+	}
+	if err := s.repo.CreateProject(ctx, entityProject); err != nil {
+		log.Println("Error creating project in repo:", err)
+		return err
+	}
+
+	project.ID = entityProject.ID
+	project.Created = entityProject.CreatedAt
+	project.Updated = entityProject.UpdatedAt
+	return nil
+}
+
+func (s *ProjectService) ListProjectsSvc(ctx context.Context, userID string) ([]*models.Project, error) {
+	// Users created before projects existed get their Inbox on first use
+	if _, err := defaultProject(ctx, s.repo, userID); err != nil {
+		return nil, err
+	}
+
+	projects, err := s.repo.ListProjects(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching projects from repo:", err)
+		return nil, err
+	}
+
+	var result []*models.Project
+	for _, project := range projects {
+		result = append(result, toProjectModel(project))
+	}
+	return result, nil
+}
+
+func (s *ProjectService) UpdateProjectSvc(ctx context.Context, project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return fmt.Errorf("%w: project name is required", globals.ErrValidation)
+	}
+
+	existing, err := ownedProject(ctx, s.repo, int(project.ID), project.UserID)
+	if err != nil {
+		return err
+	}
+
+	existing.Name = project.Name
+	if err := s.repo.UpdateProject(ctx, existing); err != nil {
+		log.Println("Error updating project in repo:", err)
+		return err
+	}
+
+	*project = *toProjectModel(existing)
+	return nil
+}
+
+func (s *ProjectService) DeleteProjectSvc(ctx context.Context, projectID int, userID string, opts *models.DeleteProject) error {
+	project, err := ownedProject(ctx, s.repo, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if project.IsDefault {
+		return fmt.Errorf("%w: the default project cannot be deleted", globals.ErrValidation)
+	}
+
+	// trashed tasks wait in the Inbox, restoring them brings them back there
+	target, err := defaultProject(ctx, s.repo, userID)
+	if err != nil {
+		return err
+	}
+	if !opts.DeleteTasks && opts.MoveTo != nil {
+		if *opts.MoveTo == project.ID {
+			return fmt.Errorf("%w: cannot move tasks into the project being deleted", globals.ErrValidation)
+		}
+		target, err = ownedProject(ctx, s.repo, int(*opts.MoveTo), userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.DeleteProject(ctx, projectID, target.ID, opts.DeleteTasks); err != nil {
+		log.Println("Error deleting project in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// ownedProject fetches a project and makes sure it belongs to the user
+func ownedProject(ctx context.Context, projects repo.ProjectRepoInterface, projectID int, userID string) (*entity.Project, error) {
+	project, err := projects.GetProjectByID(ctx, projectID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrProjectNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching project from repo:", err)
+		return nil, err
+	}
+	if project.UserID != userID {
+		return nil, globals.ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// defaultProject returns the user's Inbox, creating it when the user does not have one yet
+func defaultProject(ctx context.Context, projects repo.ProjectRepoInterface, userID string) (*entity.Project, error) {
+	project, err := projects.GetDefaultProject(ctx, userID)
+	if err == nil {
+		return project, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		log.Println("Error fetching default project from repo:", err)
+		return nil, err
+	}
+
+	project = &entity.Project{
+		UserID:    userID,
+		Name:      globals.DefaultProject,
+		IsDefault: true,
+	}
+	if err := projects.CreateProject(ctx, project); err != nil {
+		log.Println("Error creating default project in repo:", err)
+		return nil, err
+	}
+	return project, nil
+}
+
+func toProjectModel(project *entity.Project) *models.Project {
+	return &models.Project{
+		ID:        project.ID,
+		UserID:    project.UserID,
+		Name:      project.Name,
+		IsDefault: project.IsDefault,
+		Created:   project.CreatedAt,
+		Updated:   project.UpdatedAt,
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// GetTodoHistorySvc returns the revisions of a task of the user, newest first. Tasks in the
+// trash keep their history.
+func (s *TaskService) GetTodoHistorySvc(ctx context.Context, taskID int, userID string, limit, offset int) (*models.PaginatedRevisions, error) {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		if !errors.Is(err, globals.ErrTaskNotFound) {
+			return nil, err
+		}
+		if _, err := s.trashedTask(ctx, taskID, userID); err != nil {
+			return nil, globals.ErrTaskNotFound
+		}
+	}
+
+	revisions, total, err := s.revisions.ListRevisions(ctx, taskID, limit, offset)
+	if err != nil {
+		log.Println("Error fetching todo revisions from repo:", err)
+		return nil, err
+	}
+
+	result := make([]*models.Revision, 0, len(revisions))
+	for _, rev := range revisions {
+		result = append(result, toRevisionModel(rev))
+	}
+	return &models.PaginatedRevisions{
+		TotalCount: total,
+		Revisions:  result,
+	}, nil
+}
+
+// RevertTodoSvc puts a task back into the state recorded by one of its revisions. The revert
+// is saved like any other update, so it goes through the same checks and is recorded as a new
+// revision. A non-zero ifMatch must match the stored version and force allows completing a
+// task that is still blocked. It returns the updated task.
+func (s *TaskService) RevertTodoSvc(ctx context.Context, taskID, revision int, userID string, ifMatch int64, force bool) (*models.Todo, error) {
+	existing, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(existing, ifMatch); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revisions.GetRevision(ctx, taskID, revision)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrRevisionNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching todo revision from repo:", err)
+		return nil, err
+	}
+	snapshot := rev.Snapshot
+	if snapshot.Deleted {
+		return nil, fmt.Errorf("%w: revision %d deleted the task", globals.ErrValidation, revision)
+	}
+
+	statuses, err := workflowStatuses(ctx, s.statuses, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// statuses can be renamed, the id of the recorded status is looked up first
+	var status *entity.Status
+	for _, candidate := range statuses {
+		if candidate.ID == snapshot.StatusID {
+			status = candidate
+			break
+		}
+	}
+	if status == nil {
+		if status, err = findStatus(statuses, snapshot.Status); err != nil {
+			return nil, fmt.Errorf("%w: status %q of revision %d no longer exists", globals.ErrValidation, snapshot.Status, revision)
+		}
+	}
+
+	priority, ok := globals.TaskPriority[snapshot.Priority]
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid task priority in revision %d", globals.ErrValidation, revision)
+	}
+
+	updated := *existing
+	updated.Title = snapshot.Title
+	updated.Description = snapshot.Description
+	updated.Priority = priority
+	setStatus(&updated, status)
+	updated.DueAt = time.Time{}
+	if snapshot.DueAt != nil {
+		updated.DueAt = *snapshot.DueAt
+	}
+	updated.ProjectID = snapshot.ProjectID
+	updated.Recurrence = snapshot.Recurrence
+
+	change := &entity.Revision{Action: entity.RevisionRevert, ActorID: userID, RevertedTo: &rev.Revision}
+	if err := s.saveTask(ctx, existing, &updated, snapshot.Tags, true, force, change); err != nil {
+		return nil, err
+	}
+	return s.GetTodoByIDSvc(ctx, taskID, userID)
+}
+
+// applyTaskOp writes a single task together with its tags and revision in one transaction, so
+// a change is never saved without its revision
+func (s *TaskService) applyTaskOp(ctx context.Context, op *entity.TaskOp) error {
+	return s.repo.ApplyTaskOps(ctx, op.Task.UserID, []*entity.TaskOp{op}, true)
+}
+
+// taskTags returns the current tags of a task
+func (s *TaskService) taskTags(ctx context.Context, taskID int64) ([]string, error) {
+	tags, err := s.tags.ListTagsForTasks(ctx, []int64{taskID})
+	if err != nil {
+		log.Println("Error fetching todo tags from repo:", err)
+		return nil, err
+	}
+	return tags[taskID], nil
+}
+
+// newRevision builds the revision of a change from the state of the task before it, nil for a
+// new task, and after it
+func newRevision(action, actorID string, before, after *entity.TaskSnapshot) *entity.Revision {
+	return &entity.Revision{
+		Action:   action,
+		ActorID:  actorID,
+		Changes:  diffSnapshots(before, after),
+		Snapshot: after,
+	}
+}
+
+// snapshotTask captures the fields of a task that revisions track
+func snapshotTask(task *entity.Task, tags []string, deleted bool) *entity.TaskSnapshot {
+	sorted := append([]string{}, tags...)
+	sort.Strings(sorted)
+
+	snapshot := &entity.TaskSnapshot{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    globals.TaskPriorityReverse[task.Priority],
+		StatusID:    task.StatusID,
+		Status:      task.Status,
+		ProjectID:   task.ProjectID,
+		Recurrence:  task.Recurrence,
+		Tags:        sorted,
+		Deleted:     deleted,
+	}
+	if !task.DueAt.IsZero() {
+		dueAt := task.DueAt
+		snapshot.DueAt = &dueAt
+	}
+	return snapshot
+}
+
+// diffSnapshots lists the fields that differ between two states of a task under the names used
+// by models.Todo. Without a previous state every field that is set counts as changed.
+func diffSnapshots(before, after *entity.TaskSnapshot) map[string]entity.FieldChange {
+	if before == nil {
+		before = &entity.TaskSnapshot{}
+	}
+
+	changes := make(map[string]entity.FieldChange)
+	add := func(field string, changed bool, old, new interface{}) {
+		if changed {
+			changes[field] = entity.FieldChange{Old: old, New: new}
+		}
+	}
+	add("title", before.Title != after.Title, before.Title, after.Title)
+	add("description", before.Description != after.Description, before.Description, after.Description)
+	add("priority", before.Priority != after.Priority, before.Priority, after.Priority)
+	add("status", before.StatusID != after.StatusID, before.Status, after.Status)
+	add("dueAt", !equalTimes(before.DueAt, after.DueAt), before.DueAt, after.DueAt)
+	add("project_id", !equalIDs(before.ProjectID, after.ProjectID), before.ProjectID, after.ProjectID)
+	add("recurrence", before.Recurrence != after.Recurrence, before.Recurrence, after.Recurrence)
+	add("tags", strings.Join(before.Tags, ",") != strings.Join(after.Tags, ","), before.Tags, after.Tags)
+	add("deleted", before.Deleted != after.Deleted, before.Deleted, after.Deleted)
+	return changes
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func equalIDs(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func toRevisionModel(rev *entity.Revision) *models.Revision {
+	changes := make(map[string]models.FieldChange, len(rev.Changes))
+	for field, change := range rev.Changes {
+		changes[field] = models.FieldChange{Old: change.Old, New: change.New}
+	}
+	return &models.Revision{
+		Revision:   rev.Revision,
+		Action:     rev.Action,
+		ActorID:    rev.ActorID,
+		RevertedTo: rev.RevertedTo,
+		Changes:    changes,
+		Created:    rev.CreatedAt,
+	}
+}
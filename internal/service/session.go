@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// Reasons recorded for failed login attempts
+const (
+	loginUnknownEmail     = "unknown email"
+	loginWrongPassword    = "wrong password"
+	loginEmailNotVerified = "email not verified"
+)
+
+// maxUserAgentLength limits the number of characters kept of a user agent
+const maxUserAgentLength = 255
+
+// ListSessionsSvc returns the active sessions of the user, the one the access token with
+// currentJTI belongs to is flagged as current
+func (s *UserService) ListSessionsSvc(ctx context.Context, userID, currentJTI string) ([]*models.Session, error) {
+	sessions, err := s.sessions.ListSessions(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching sessions from repo:", err)
+		return nil, err
+	}
+
+	var currentID string
+	if token, err := s.tokens.GetRefreshTokenByAccessJTI(ctx, currentJTI); err == nil {
+		currentID = token.FamilyID
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		log.Println("Error fetching refresh token from repo:", err)
+		return nil, err
+	}
+
+	result := make([]*models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &models.Session{
+			ID:        session.ID,
+			UserAgent: session.UserAgent,
+			IP:        session.IP,
+			Created:   session.CreatedAt,
+			LastSeen:  session.LastSeenAt,
+			Expires:   session.ExpiresAt,
+			Current:   session.ID == currentID,
+		})
+	}
+	return result, nil
+}
+
+// RevokeSessionSvc logs the user out of one session, its tokens stop working right away
+func (s *UserService) RevokeSessionSvc(ctx context.Context, userID, sessionID string) error {
+	session, err := s.sessions.GetSessionByID(ctx, sessionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return globals.ErrSessionNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching session from repo:", err)
+		return err
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return globals.ErrSessionNotFound
+	}
+	return s.revokeFamily(ctx, sessionID)
+}
+
+// ListLoginHistorySvc returns the successful and failed logins on the account, newest first
+func (s *UserService) ListLoginHistorySvc(ctx context.Context, userID string, limit, offset int) (*models.PaginatedLoginAttempts, error) {
+	attempts, total, err := s.sessions.ListLoginAttempts(ctx, userID, limit, offset)
+	if err != nil {
+		log.Println("Error fetching login attempts from repo:", err)
+		return nil, err
+	}
+
+	result := make([]*models.LoginAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		result = append(result, &models.LoginAttempt{
+			ID:        attempt.ID,
+			Success:   attempt.Success,
+			Reason:    attempt.Reason,
+			UserAgent: attempt.UserAgent,
+			IP:        attempt.IP,
+			Created:   attempt.CreatedAt,
+		})
+	}
+	return &models.PaginatedLoginAttempts{
+		TotalCount: total,
+		Attempts:   result,
+	}, nil
+}
+
+// startSession records a new login of the user from the client
+func (s *UserService) startSession(ctx context.Context, userID string, client *models.Client) (*entity.Session, error) {
+	session := s.newSession(userID, client)
+	session.ID = uuid.NewString()
+	if err := s.sessions.CreateSession(ctx, session); err != nil {
+		log.Println("Error creating session in repo:", err)
+		return nil, err
+	}
+	return session, nil
+}
+
+// newSession describes a session of the user used from the client, it lasts as long as a
+// refresh token issued now
+func (s *UserService) newSession(userID string, client *models.Client) *entity.Session {
+	return &entity.Session{
+		UserID:    userID,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+		IP:        client.IP,
+		ExpiresAt: time.Now().Add(time.Duration(s.cnfg.RefreshTokenDays) * 24 * time.Hour),
+	}
+}
+
+// recordLoginAttempt stores a login attempt, an empty reason means it succeeded. The history
+// is informational, so a failure is only logged.
+func (s *UserService) recordLoginAttempt(ctx context.Context, userID, email, reason string, client *models.Client) {
+	attempt := &entity.LoginAttempt{
+		UserID:    userID,
+		Email:     truncate(email, 63),
+		Success:   reason == "",
+		Reason:    reason,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+		IP:        client.IP,
+	}
+	if err := s.sessions.CreateLoginAttempt(ctx, attempt); err != nil {
+		log.Println("Error creating login attempt in repo:", err)
+	}
+}
+
+// truncate shortens a string to at most max characters
+func truncate(value string, max int) string {
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+	return string([]rune(value)[:max])
+}
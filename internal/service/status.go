@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	repo "github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+	service "github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+	"go.uber.org/zap"
+)
+
+// maxStatusNameLength matches the size of the statuses.name column
+const maxStatusNameLength = 63
+
+// defaultStatusColor is used for statuses created without a color
+const defaultStatusColor = "#9e9e9e"
+
+var statusColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type StatusService struct {
+	repo   repo.StatusRepoInterface
+	logger *zap.Logger
+}
+
+func NewStatusService(repo repo.StatusRepoInterface, logger *zap.Logger) service.StatusServiceInterface {
+	return &StatusService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *StatusService) ListStatusesSvc(ctx context.Context, userID string) ([]*models.Status, error) {
+	statuses, err := workflowStatuses(ctx, s.repo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.repo.ListTransitions(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching status transitions from repo:", err)
+		return nil, err
+	}
+
+	var result []*models.Status
+	for _, status := range statuses {
+		model := toStatusModel(status)
+		model.Transitions = statusNames(statuses, transitions[status.ID])
+		result = append(result, model)
+	}
+	return result, nil
+}
+
+func (s *StatusService) CreateStatusSvc(ctx context.Context, status *models.Status) error {
+	statuses, err := workflowStatuses(ctx, s.repo, status.UserID)
+	if err != nil {
+		return err
+	}
+
+	entityStatus := &entity.Status{
+		UserID:     status.UserID,
+		Name:       status.Name,
+		Position:   status.Position,
+		Color:      status.Color,
+		IsTerminal: status.IsTerminal,
+	}
+	if entityStatus.Color == "" {
+		entityStatus.Color = defaultStatusColor
+	}
+	if entityStatus.Position == 0 && len(statuses) > 0 {
+		entityStatus.Position = statuses[len(statuses)-1].Position + 1
+	}
+	if err := validateStatus(entityStatus, statuses); err != nil {
+		return err
+	}
+
+	if err := s.repo.CreateStatus(ctx, entityStatus); err != nil {
+		log.Println("Error creating status in repo:", err)
+		return err
+	}
+
+	*status = *toStatusModel(entityStatus)
+	return nil
+}
+
+func (s *StatusService) UpdateStatusSvc(ctx context.Context, statusID int, userID string, patch *models.StatusPatch) (*models.Status, error) {
+	statuses, err := workflowStatuses(ctx, s.repo, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := ownedStatus(ctx, s.repo, statusID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if patch.Name.Set {
+		updated.Name = patch.Name.Value
+	}
+	if patch.Position.Set {
+		updated.Position = patch.Position.Value
+	}
+	if patch.Color.Set {
+		updated.Color = patch.Color.Value
+	}
+	if patch.IsTerminal.Set {
+		updated.IsTerminal = patch.IsTerminal.Value
+	}
+	if err := validateStatus(&updated, statuses); err != nil {
+		return nil, err
+	}
+	if err := checkWorkflow(statuses, &updated, false); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateStatus(ctx, &updated); err != nil {
+		log.Println("Error updating status in repo:", err)
+		return nil, err
+	}
+
+	transitions, err := s.repo.ListTransitions(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching status transitions from repo:", err)
+		return nil, err
+	}
+	status := toStatusModel(&updated)
+	status.Transitions = statusNames(statuses, transitions[updated.ID])
+	return status, nil
+}
+
+// SetTransitionsSvc replaces the statuses a task may move to from a status, given by name. An
+// empty list lets tasks move from it to any status.
+func (s *StatusService) SetTransitionsSvc(ctx context.Context, statusID int, userID string, req *models.Transitions) (*models.Status, error) {
+	statuses, err := workflowStatuses(ctx, s.repo, userID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := ownedStatus(ctx, s.repo, statusID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(req.To))
+	for _, name := range req.To {
+		to, err := findStatus(statuses, name)
+		if err != nil {
+			return nil, err
+		}
+		if to.ID == from.ID {
+			return nil, fmt.Errorf("%w: a status cannot transition to itself", globals.ErrValidation)
+		}
+		ids = append(ids, to.ID)
+	}
+
+	if err := s.repo.SetTransitions(ctx, from.ID, ids); err != nil {
+		log.Println("Error setting status transitions in repo:", err)
+		return nil, err
+	}
+
+	status := toStatusModel(from)
+	status.Transitions = statusNames(statuses, ids)
+	return status, nil
+}
+
+func (s *StatusService) DeleteStatusSvc(ctx context.Context, statusID int, userID string, opts *models.DeleteStatus) error {
+	statuses, err := workflowStatuses(ctx, s.repo, userID)
+	if err != nil {
+		return err
+	}
+	status, err := ownedStatus(ctx, s.repo, statusID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkWorkflow(statuses, status, true); err != nil {
+		return err
+	}
+
+	var target *entity.Status
+	if opts.MoveTo != nil {
+		if *opts.MoveTo == status.ID {
+			return fmt.Errorf("%w: cannot move tasks into the status being deleted", globals.ErrValidation)
+		}
+		if target, err = ownedStatus(ctx, s.repo, int(*opts.MoveTo), userID); err != nil {
+			return err
+		}
+	} else {
+		for _, candidate := range statuses {
+			if candidate.ID != status.ID && candidate.IsTerminal == status.IsTerminal {
+				target = candidate
+				break
+			}
+		}
+	}
+
+	if err := s.repo.DeleteStatus(ctx, statusID, target.ID); err != nil {
+		log.Println("Error deleting status in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// validateStatus checks the fields of a status and that its name is not used by another status
+func validateStatus(status *entity.Status, statuses []*entity.Status) error {
+	status.Name = strings.TrimSpace(status.Name)
+	if status.Name == "" {
+		return fmt.Errorf("%w: status name is required", globals.ErrValidation)
+	}
+	if len(status.Name) > maxStatusNameLength {
+		return fmt.Errorf("%w: status name is longer than %d characters", globals.ErrValidation, maxStatusNameLength)
+	}
+	if strings.EqualFold(status.Name, globals.AllStatuses) {
+		return fmt.Errorf("%w: %s is reserved", globals.ErrValidation, globals.AllStatuses)
+	}
+	if !statusColorPattern.MatchString(status.Color) {
+		return fmt.Errorf("%w: color must look like #rrggbb", globals.ErrValidation)
+	}
+	for _, other := range statuses {
+		if other.ID != status.ID && strings.EqualFold(other.Name, status.Name) {
+			return fmt.Errorf("%w: status %q already exists", globals.ErrValidation, other.Name)
+		}
+	}
+	return nil
+}
+
+// checkWorkflow makes sure a workflow keeps at least one open and one terminal status once
+// changed is updated, or removed when remove is set
+func checkWorkflow(statuses []*entity.Status, changed *entity.Status, remove bool) error {
+	open, terminal := 0, 0
+	for _, status := range statuses {
+		if status.ID == changed.ID {
+			if remove {
+				continue
+			}
+			status = changed
+		}
+		if status.IsTerminal {
+			terminal++
+		} else {
+			open++
+		}
+	}
+	if open == 0 || terminal == 0 {
+		return fmt.Errorf("%w: the workflow needs at least one open and one terminal status", globals.ErrValidation)
+	}
+	return nil
+}
+
+// checkTransition fails with a TransitionError when the status a task leaves restricts where
+// it can go next and the new status is not one of those. Keeping the status is always allowed.
+func checkTransition(statuses []*entity.Status, transitions map[int64][]int64, existing, updated *entity.Task) error {
+	allowed := transitions[existing.StatusID]
+	if existing.StatusID == updated.StatusID || len(allowed) == 0 {
+		return nil
+	}
+	for _, id := range allowed {
+		if id == updated.StatusID {
+			return nil
+		}
+	}
+	return &globals.TransitionError{
+		From:    existing.Status,
+		To:      updated.Status,
+		Allowed: statusNames(statuses, allowed),
+	}
+}
+
+// statusNames lists the names of the statuses with the given ids in workflow order
+func statusNames(statuses []*entity.Status, ids []int64) []string {
+	names := []string{}
+	for _, status := range statuses {
+		for _, id := range ids {
+			if id == status.ID {
+				names = append(names, status.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// ownedStatus fetches a status and makes sure it belongs to the user
+func ownedStatus(ctx context.Context, statuses repo.StatusRepoInterface, statusID int, userID string) (*entity.Status, error) {
+	status, err := statuses.GetStatusByID(ctx, statusID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrStatusNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching status from repo:", err)
+		return nil, err
+	}
+	if status.UserID != userID {
+		return nil, globals.ErrStatusNotFound
+	}
+	return status, nil
+}
+
+// workflowStatuses returns the statuses of a user in order, users that have none yet get the
+// default PENDING and COMPLETED statuses
+func workflowStatuses(ctx context.Context, statuses repo.StatusRepoInterface, userID string) ([]*entity.Status, error) {
+	list, err := statuses.ListStatuses(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching statuses from repo:", err)
+		return nil, err
+	}
+	if len(list) > 0 {
+		return list, nil
+	}
+
+	if err := statuses.CreateDefaultStatuses(ctx, userID); err != nil {
+		log.Println("Error creating default statuses in repo:", err)
+		return nil, err
+	}
+	if list, err = statuses.ListStatuses(ctx, userID); err != nil {
+		log.Println("Error fetching statuses from repo:", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// findStatus looks a status up by name, case-insensitively
+func findStatus(statuses []*entity.Status, name string) (*entity.Status, error) {
+	for _, status := range statuses {
+		if strings.EqualFold(status.Name, strings.TrimSpace(name)) {
+			return status, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: invalid task status %q", globals.ErrValidation, name)
+}
+
+// initialStatus is where new tasks start, the first open status of the workflow
+func initialStatus(statuses []*entity.Status) *entity.Status {
+	for _, status := range statuses {
+		if !status.IsTerminal {
+			return status
+		}
+	}
+	return statuses[0]
+}
+
+// doneStatus is where completed tasks go, the first terminal status of the workflow
+func doneStatus(statuses []*entity.Status) *entity.Status {
+	for _, status := range statuses {
+		if status.IsTerminal {
+			return status
+		}
+	}
+	return statuses[len(statuses)-1]
+}
+
+// setStatus moves a task to a status
+func setStatus(task *entity.Task, status *entity.Status) {
+	task.StatusID = status.ID
+	task.Status = status.Name
+	task.Terminal = status.IsTerminal
+}
+
+func toStatusModel(status *entity.Status) *models.Status {
+	return &models.Status{
+		ID:         status.ID,
+		UserID:     status.UserID,
+		Name:       status.Name,
+		Position:   status.Position,
+		Color:      status.Color,
+		IsTerminal: status.IsTerminal,
+		Created:    status.CreatedAt,
+		Updated:    status.UpdatedAt,
+	}
+}
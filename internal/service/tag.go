@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	repo "github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
+	service "github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+	"go.uber.org/zap"
+)
+
+// maxTagLength matches the size of the tags.name column
+const maxTagLength = 63
+
+type TagService struct {
+	repo   repo.TagRepoInterface
+	logger *zap.Logger
+}
+
+func NewTagService(repo repo.TagRepoInterface, logger *zap.Logger) service.TagServiceInterface {
+	return &TagService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *TagService) ListTagsSvc(ctx context.Context, userID string) ([]*models.Tag, error) {
+	tags, err := s.repo.ListTags(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching tags from repo:", err)
+		return nil, err
+	}
+
+	var result []*models.Tag
+	for _, tag := range tags {
+		result = append(result, &models.Tag{
+			ID:         tag.ID,
+			Name:       tag.Name,
+			UsageCount: tag.UsageCount,
+			Created:    tag.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *TagService) RenameTagSvc(ctx context.Context, tagID int, userID, name string) error {
+	names, err := normalizeTags([]string{name})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%w: tag name is required", globals.ErrValidation)
+	}
+
+	if _, err := s.ownedTag(ctx, tagID, userID); err != nil {
+		return err
+	}
+
+	tags, err := s.repo.ListTags(ctx, userID)
+	if err != nil {
+		log.Println("Error fetching tags from repo:", err)
+		return err
+	}
+	for _, tag := range tags {
+		if tag.Name == names[0] && tag.ID != int64(tagID) {
+			return fmt.Errorf("tag %q already exists, merge the tags instead", names[0])
+		}
+	}
+
+	if err := s.repo.RenameTag(ctx, tagID, names[0]); err != nil {
+		log.Println("Error renaming tag in repo:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *TagService) MergeTagsSvc(ctx context.Context, sourceID, targetID int, userID string) error {
+	if sourceID == targetID {
+		return fmt.Errorf("%w: cannot merge a tag into itself", globals.ErrValidation)
+	}
+	if _, err := s.ownedTag(ctx, sourceID, userID); err != nil {
+		return err
+	}
+	if _, err := s.ownedTag(ctx, targetID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.MergeTags(ctx, sourceID, targetID); err != nil {
+		log.Println("Error merging tags in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// ownedTag fetches a tag and makes sure it belongs to the user
+func (s *TagService) ownedTag(ctx context.Context, tagID int, userID string) (*entity.Tag, error) {
+	tag, err := s.repo.GetTagByID(ctx, tagID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrTagNotFound
+	}
+	if err != nil {
+		log.Println("Error fetching tag from repo:", err)
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, globals.ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// normalizeTags trims, lower cases and de-duplicates tag names so they match case-insensitively
+func normalizeTags(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if len(name) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", globals.ErrValidation, name, maxTagLength)
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result, nil
+}
@@ -3,120 +3,601 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/shivarajshanthaiah/todo-app/internal/models"
 	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
 	repo "github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
 	service "github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
 	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+	"github.com/shivarajshanthaiah/todo-app/pkg/rrule"
 	"go.uber.org/zap"
 )
 
 type TaskService struct {
-	repo   repo.TaskRepoInterface
-	logger *zap.Logger
+	repo      repo.TaskRepoInterface
+	projects  repo.ProjectRepoInterface
+	tags      repo.TagRepoInterface
+	deps      repo.DependencyRepoInterface
+	statuses  repo.StatusRepoInterface
+	revisions repo.RevisionRepoInterface
+	comments  repo.CommentRepoInterface
+	checklist repo.ChecklistRepoInterface
+	logger    *zap.Logger
+
+	// autoComplete completes a task once every item of its checklist is done
+	autoComplete bool
 }
 
-func NewTaskService(repo repo.TaskRepoInterface, logger *zap.Logger) service.TaskServiceInterface {
+func NewTaskService(repo repo.TaskRepoInterface, projects repo.ProjectRepoInterface, tags repo.TagRepoInterface, deps repo.DependencyRepoInterface, statuses repo.StatusRepoInterface, revisions repo.RevisionRepoInterface, comments repo.CommentRepoInterface, checklist repo.ChecklistRepoInterface, autoComplete bool, logger *zap.Logger) service.TaskServiceInterface {
 	return &TaskService{
-		repo: repo,
-		logger: logger,
+		repo:      repo,
+		projects:  projects,
+		tags:      tags,
+		deps:      deps,
+		statuses:  statuses,
+		revisions: revisions,
+		comments:  comments,
+		checklist: checklist,
+		logger:    logger,
+
+		autoComplete: autoComplete,
 	}
 }
 
 func (s *TaskService) CreateTodoSvc(ctx context.Context, todo *models.Todo) error {
+	// A subtask can only be created under a task the user owns
+	var parent *entity.Task
+	if todo.ParentID != nil {
+		var err error
+		parent, err = s.repo.GetTodoByID(ctx, int(*todo.ParentID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("parent %w", globals.ErrTaskNotFound)
+		}
+		if err != nil {
+			log.Println("Error fetching parent todo from repo:", err)
+			return err
+		}
+		if parent.UserID != todo.UserID {
+			return fmt.Errorf("parent %w", globals.ErrTaskNotFound)
+		}
+	}
+
+	statuses, err := workflowStatuses(ctx, s.statuses, todo.UserID)
+	if err != nil {
+		return err
+	}
+
+	entityTask, tags, err := s.newTask(ctx, todo, parent, statuses)
+	if err != nil {
+		return err
+	}
+
+	revision := newRevision(entity.RevisionCreate, todo.UserID, nil, snapshotTask(entityTask, tags, false))
+	op := &entity.TaskOp{Kind: entity.TaskOpCreate, Task: entityTask, Tags: tags, SetTags: len(tags) > 0, Revision: revision}
+	if err := s.applyTaskOp(ctx, op); err != nil {
+		log.Println("Error creating todo in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// newTask validates a todo to be created under parent (nil for a top level task) and maps it to
+// an entity along with its normalized tags. The todo's project is resolved in place and
+// tasks without a status start in the first open status of the user's workflow.
+func (s *TaskService) newTask(ctx context.Context, todo *models.Todo, parent *entity.Task, statuses []*entity.Status) (*entity.Task, []string, error) {
+	if strings.TrimSpace(todo.Title) == "" {
+		return nil, nil, fmt.Errorf("%w: title cannot be empty", globals.ErrValidation)
+	}
+
 	// Convert Priority
 	priorityVal, ok := globals.TaskPriority[todo.Priority]
 	if !ok {
 		log.Println("Invalid priority value:", todo.Priority)
-		return errors.New("invalid task priority")
+		return nil, nil, fmt.Errorf("%w: invalid task priority: %s", globals.ErrValidation, todo.Priority)
 	}
 
-	// Convert Status
-	statusVal, ok := globals.TaskStatus[todo.Status]
-	if !ok {
-		log.Println("Invalid status value:", todo.Status)
-		return errors.New("invalid task status")
+	// Resolve the status against the user's workflow
+	status := initialStatus(statuses)
+	if todo.Status != "" {
+		var err error
+		if status, err = findStatus(statuses, todo.Status); err != nil {
+			log.Println("Invalid status value:", todo.Status)
+			return nil, nil, err
+		}
+	}
+
+	tags, err := normalizeTags(todo.Tags)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recurrence, err := normalizeRecurrence(todo.Recurrence)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Tasks go to the requested project, the parent's project, or the user's Inbox
+	switch {
+	case todo.ProjectID != nil:
+		if _, err := ownedProject(ctx, s.projects, int(*todo.ProjectID), todo.UserID); err != nil {
+			return nil, nil, err
+		}
+	case parent != nil && parent.ProjectID != nil:
+		todo.ProjectID = parent.ProjectID
+	default:
+		inbox, err := defaultProject(ctx, s.projects, todo.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		todo.ProjectID = &inbox.ID
 	}
 
 	// Map model to entity
-	entityTask := entity.Task{
+	task := &entity.Task{
 		UserID:      todo.UserID,
+		ParentID:    todo.ParentID,
+		ProjectID:   todo.ProjectID,
 		Title:       todo.Title,
 		Description: todo.Description,
 		Priority:    priorityVal,
-		Status:      statusVal,
 		DueAt:       todo.DueAt,
+		Recurrence:  recurrence,
+		Occurrence:  1,
 	}
+	setStatus(task, status)
+	return task, tags, nil
+}
 
-	err := s.repo.CreateTodo(ctx, &entityTask)
+func (s *TaskService) GetTodoByUserIDSvc(ctx context.Context, userID string, req *models.Request) (*models.PaginatedTodos, error) {
+	tagsAny, err := normalizeTags(req.TagsAny)
 	if err != nil {
-		log.Println("Error creating todo in repo:", err)
-		return err
+		return nil, err
+	}
+	tagsAll, err := normalizeTags(req.TagsAll)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := make([]int, 0, len(req.Priorities))
+	for _, priority := range req.Priorities {
+		priorities = append(priorities, globals.TaskPriority[priority])
+	}
+
+	sort := make([]entity.SortKey, 0, len(req.Sort))
+	for _, field := range req.Sort {
+		sort = append(sort, entity.SortKey{Field: field.Field, Desc: field.Direction == "desc"})
+	}
+
+	statusID, err := s.statusFilter(ctx, userID, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &entity.TaskFilter{
+		StatusID:    statusID,
+		Priorities:  priorities,
+		ParentID:    req.ParentID,
+		ProjectID:   req.ProjectID,
+		TagsAny:     tagsAny,
+		TagsAll:     tagsAll,
+		RootsOnly:   req.Tree && req.ParentID == nil,
+		Actionable:  req.Actionable,
+		DueFrom:     req.DueFrom,
+		DueTo:       req.DueTo,
+		Overdue:     req.Overdue,
+		NoDueDate:   req.NoDueDate,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		UpdatedFrom: req.UpdatedFrom,
+		UpdatedTo:   req.UpdatedTo,
+		Sort:        sort,
+		Limit:       req.Limit + 1, // one extra row tells whether another page follows
+		Offset:      req.Offset,
 	}
-	return nil
-}
 
-func (s *TaskService) GetTodoByUserIDSvc(ctx context.Context, userID, status string, limit, offset int) (*models.PaginatedTodos, error) {
+	// Cursor pages skip the total count unless asked for, it costs a second query
+	if req.Cursor != "" {
+		if filter.Cursor, err = decodeCursor(req.Cursor, sort); err != nil {
+			return nil, err
+		}
+		filter.Offset = 0
+	}
+	filter.CountTotal = req.Cursor == ""
+	if req.IncludeTotal != nil {
+		filter.CountTotal = *req.IncludeTotal
+	}
 
-	tasks, total, err := s.repo.ListAllTodos(ctx, userID, status, limit, offset)
+	tasks, total, err := s.repo.ListAllTodos(ctx, userID, filter)
 	if err != nil {
 		log.Println("Error fetching todos from repo:", err)
 		return nil, err
 	}
 
+	hasMore := len(tasks) > req.Limit
+	if hasMore {
+		tasks = tasks[:req.Limit]
+	}
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if backward {
+		// a backward page is read in mirrored order
+		for i, j := 0, len(tasks)-1; i < j; i, j = i+1, j-1 {
+			tasks[i], tasks[j] = tasks[j], tasks[i]
+		}
+	}
+
+	page := &models.PaginatedTodos{}
+	if filter.CountTotal {
+		page.TotalCount = &total
+	}
+	if len(tasks) > 0 {
+		first, last := tasks[0], tasks[len(tasks)-1]
+		// going backward there is always the page we came from after this one
+		if hasMore || backward {
+			page.NextCursor = encodeCursor(last, sort, false)
+		}
+		// going forward there is a previous page unless this is the first one
+		if (backward && hasMore) || (!backward && (filter.Cursor != nil || req.Offset > 0)) {
+			page.PrevCursor = encodeCursor(first, sort, true)
+		}
+	}
+
 	var todos []*models.Todo
 	for _, task := range tasks {
-		todos = append(todos, &models.Todo{
-			ID:          task.ID,
-			UserID:      task.UserID,
-			Title:       task.Title,
-			Description: task.Description,
-			Priority:    globals.TaskPriorityReverse[task.Priority],
-			Status:      globals.TaskStatusReverse[task.Status],
-			DueAt:       task.DueAt,
-			Created:     task.CreatedAt,
-			Updated:     task.UpdatedAt,
+		todos = append(todos, toTodoModel(task))
+	}
+
+	if req.Tree && len(todos) > 0 {
+		if err := s.attachSubtasks(ctx, userID, todos); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.attachTags(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachDependencies(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachCommentCounts(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachChecklistProgress(ctx, todos); err != nil {
+		return nil, err
+	}
+
+	page.Todos = todos
+	return page, nil
+}
+
+// GetTodoByIDSvc returns a single task of the user with its tags and nested subtasks
+func (s *TaskService) GetTodoByIDSvc(ctx context.Context, taskID int, userID string) (*models.Todo, error) {
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	todo := toTodoModel(task)
+	todos := []*models.Todo{todo}
+	if err := s.attachSubtasks(ctx, userID, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachDependencies(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachCommentCounts(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachChecklistProgress(ctx, todos); err != nil {
+		return nil, err
+	}
+	if todo.Items, err = s.checklistItems(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+func (s *TaskService) SearchTodosSvc(ctx context.Context, userID string, req *models.SearchRequest) (*models.SearchResults, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: search query is required", globals.ErrValidation)
+	}
+
+	statusID, err := s.statusFilter(ctx, userID, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, total, err := s.repo.SearchTodos(ctx, userID, query, statusID, req.Limit, req.Offset)
+	if err != nil {
+		log.Println("Error searching todos in repo:", err)
+		return nil, err
+	}
+
+	results := make([]*models.SearchResult, 0, len(hits))
+	todos := make([]*models.Todo, 0, len(hits))
+	for _, hit := range hits {
+		todo := toTodoModel(hit.Task)
+		todos = append(todos, todo)
+		results = append(results, &models.SearchResult{
+			Todo:               todo,
+			Rank:               hit.Rank,
+			TitleSnippet:       hit.TitleSnippet,
+			DescriptionSnippet: hit.DescriptionSnippet,
 		})
 	}
 
-	return &models.PaginatedTodos{
+	if err := s.attachTags(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachCommentCounts(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachChecklistProgress(ctx, todos); err != nil {
+		return nil, err
+	}
+
+	return &models.SearchResults{
 		TotalCount: total,
-		Todos:      todos,
+		Results:    results,
 	}, nil
 }
 
-func (s *TaskService) UpdateTodoByIDSvc(ctx context.Context, todo *models.Todo) error {
+// attachSubtasks loads all descendants of the given todos and nests them under their parents
+func (s *TaskService) attachSubtasks(ctx context.Context, userID string, roots []*models.Todo) error {
+	rootIDs := make([]int64, 0, len(roots))
+	nodes := make(map[int64]*models.Todo, len(roots))
+	for _, todo := range roots {
+		rootIDs = append(rootIDs, todo.ID)
+		nodes[todo.ID] = todo
+	}
+
+	subtasks, err := s.repo.ListSubtasks(ctx, userID, rootIDs)
+	if err != nil {
+		log.Println("Error fetching subtasks from repo:", err)
+		return err
+	}
+
+	children := make([]*models.Todo, 0, len(subtasks))
+	for _, task := range subtasks {
+		todo := toTodoModel(task)
+		nodes[todo.ID] = todo
+		children = append(children, todo)
+	}
+	for _, child := range children {
+		if parent, ok := nodes[*child.ParentID]; ok {
+			parent.Subtasks = append(parent.Subtasks, child)
+		}
+	}
+	return nil
+}
+
+// UpdateTodoByIDSvc replaces every editable field of a task (PUT), fields left out are cleared,
+// the recurrence rule too. The project is only changed when one is given. A non-zero todo.Version must match the
+// stored version, the new version is written back into todo. force allows completing a
+// task that is still blocked.
+func (s *TaskService) UpdateTodoByIDSvc(ctx context.Context, todo *models.Todo, force bool) error {
+	if strings.TrimSpace(todo.Title) == "" {
+		return fmt.Errorf("%w: title cannot be empty", globals.ErrValidation)
+	}
+
 	// Convert Priority
 	priorityVal, ok := globals.TaskPriority[todo.Priority]
 	if !ok {
 		log.Println("Invalid priority value:", todo.Priority)
-		return errors.New("invalid task priority")
+		return fmt.Errorf("%w: invalid task priority", globals.ErrValidation)
 	}
 
-	// Convert Status
-	statusVal, ok := globals.TaskStatus[todo.Status]
-	if !ok {
+	// Resolve the status against the user's workflow
+	statuses, err := workflowStatuses(ctx, s.statuses, todo.UserID)
+	if err != nil {
+		return err
+	}
+	status, err := findStatus(statuses, todo.Status)
+	if err != nil {
 		log.Println("Invalid status value:", todo.Status)
-		return errors.New("invalid task status")
+		return err
+	}
+
+	existing, err := s.ownedTask(ctx, int(todo.ID), todo.UserID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(existing, todo.Version); err != nil {
+		return err
+	}
+
+	tags, err := normalizeTags(todo.Tags)
+	if err != nil {
+		return err
+	}
+
+	recurrence, err := normalizeRecurrence(todo.Recurrence)
+	if err != nil {
+		return err
 	}
 
 	// Map model to entity
-	entityTask := &entity.Task{
-		ID:          todo.ID,
-		UserID:      todo.UserID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		Priority:    priorityVal,
-		Status:      statusVal,
-		DueAt:       todo.DueAt,
+	entityTask := *existing
+	entityTask.Title = todo.Title
+	entityTask.Description = todo.Description
+	entityTask.Priority = priorityVal
+	setStatus(&entityTask, status)
+	entityTask.DueAt = todo.DueAt
+	entityTask.Recurrence = recurrence
+	if todo.ProjectID != nil {
+		entityTask.ProjectID = todo.ProjectID
 	}
 
-	log.Println("modified task", entityTask)
+	revision := &entity.Revision{Action: entity.RevisionUpdate, ActorID: todo.UserID}
+	if err := s.saveTask(ctx, existing, &entityTask, tags, true, force, revision); err != nil {
+		return err
+	}
+	// the completion and the next instance of a repeating task are one write, so this is
+	// the version the task is left at
+	todo.Version = entityTask.Version
+	return nil
+}
 
-	err := s.repo.UpdateTodoByID(ctx, int(todo.ID), entityTask)
+// PatchTodoSvc applies a JSON merge patch (RFC 7396) to a task, only the fields present in the
+// patch change and null clears the optional ones. A non-zero ifMatch must match the stored
+// version and force allows completing a task that is still blocked. It returns the updated task.
+func (s *TaskService) PatchTodoSvc(ctx context.Context, taskID int, userID string, ifMatch int64, force bool, patch *models.TodoPatch) (*models.Todo, error) {
+	existing, err := s.ownedTask(ctx, taskID, userID)
 	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(existing, ifMatch); err != nil {
+		return nil, err
+	}
+
+	var statuses []*entity.Status
+	if patch.Status.Set {
+		if statuses, err = workflowStatuses(ctx, s.statuses, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, tags, err := applyPatch(existing, patch, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &entity.Revision{Action: entity.RevisionUpdate, ActorID: userID}
+	if err := s.saveTask(ctx, existing, updated, tags, patch.Tags.Set, force, revision); err != nil {
+		return nil, err
+	}
+	return s.GetTodoByIDSvc(ctx, taskID, userID)
+}
+
+// applyPatch returns a copy of existing with the merge patch applied, along with the normalized
+// tags when the patch sets them. statuses is the user's workflow, only needed to change the status.
+func applyPatch(existing *entity.Task, patch *models.TodoPatch, statuses []*entity.Status) (*entity.Task, []string, error) {
+	updated := *existing
+	if patch.Title.Set {
+		if patch.Title.Null || strings.TrimSpace(patch.Title.Value) == "" {
+			return nil, nil, fmt.Errorf("%w: title cannot be empty", globals.ErrValidation)
+		}
+		updated.Title = patch.Title.Value
+	}
+	if patch.Description.Set {
+		updated.Description = patch.Description.Value // null clears it
+	}
+	if patch.Priority.Set {
+		priorityVal, ok := globals.TaskPriority[patch.Priority.Value]
+		if patch.Priority.Null || !ok {
+			return nil, nil, fmt.Errorf("%w: invalid task priority", globals.ErrValidation)
+		}
+		updated.Priority = priorityVal
+	}
+	if patch.Status.Set {
+		if patch.Status.Null {
+			return nil, nil, fmt.Errorf("%w: status cannot be null", globals.ErrValidation)
+		}
+		status, err := findStatus(statuses, patch.Status.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		setStatus(&updated, status)
+	}
+	if patch.DueAt.Set {
+		updated.DueAt = patch.DueAt.Value // null clears it
+	}
+	if patch.ProjectID.Set {
+		if patch.ProjectID.Null {
+			return nil, nil, fmt.Errorf("%w: project_id cannot be null", globals.ErrValidation)
+		}
+		updated.ProjectID = &patch.ProjectID.Value
+	}
+
+	var tags []string
+	if patch.Tags.Set {
+		var err error
+		if tags, err = normalizeTags(patch.Tags.Value); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &updated, tags, nil
+}
+
+// saveTask stores an updated task after checking the rules shared by full and partial
+// updates, including the allowed status transitions. The tags are only replaced when setTags
+// is true, an empty list clears them. force allows completing a task whose blockers are still
+// pending. revision names the action and actor of the change, it is completed with the diff
+// and recorded together with the task.
+func (s *TaskService) saveTask(ctx context.Context, existing, updated *entity.Task, tags []string, setTags, force bool, revision *entity.Revision) error {
+	// Moving a task is only allowed into one of the user's own projects
+	if updated.ProjectID != nil && (existing.ProjectID == nil || *updated.ProjectID != *existing.ProjectID) {
+		if _, err := ownedProject(ctx, s.projects, int(*updated.ProjectID), updated.UserID); err != nil {
+			return err
+		}
+	}
+
+	// The workflow may restrict which statuses a task can move to
+	if updated.StatusID != existing.StatusID {
+		if err := s.checkTransition(ctx, existing, updated); err != nil {
+			return err
+		}
+	}
+
+	// A parent can only be completed once all of its subtasks are completed
+	if updated.Terminal && !existing.Terminal {
+		open, err := s.repo.CountOpenSubtasks(ctx, int(updated.ID))
+		if err != nil {
+			log.Println("Error counting open subtasks in repo:", err)
+			return err
+		}
+		if open > 0 {
+			return fmt.Errorf("task has %d subtasks that are not completed", open)
+		}
+	}
+
+	// A task waiting on pending blockers can only be completed when forced
+	if updated.Terminal && !existing.Terminal && !force {
+		blockers, err := s.deps.ListBlockers(ctx, []int64{updated.ID})
+		if err != nil {
+			log.Println("Error fetching todo blockers from repo:", err)
+			return err
+		}
+		if pending := openBlockers(blockers[updated.ID]); pending > 0 {
+			return fmt.Errorf("%w: %d blockers are not completed", globals.ErrTaskBlocked, pending)
+		}
+	}
+
+	oldTags, err := s.taskTags(ctx, existing.ID)
+	if err != nil {
+		return err
+	}
+	newTags := oldTags
+	if setTags {
+		newTags = tags
+	}
+
+	// Completing an instance of a repeating task schedules the next one in the same transaction
+	var next *entity.TaskOp
+	if updated.Recurrence != "" && !existing.Terminal && updated.Terminal {
+		statuses, err := workflowStatuses(ctx, s.statuses, updated.UserID)
+		if err != nil {
+			return err
+		}
+		if next, err = nextOccurrence(updated, statuses, newTags); err != nil {
+			return err
+		}
+		updated.Recurrence = ""
+	}
+
+	after := snapshotTask(updated, newTags, false)
+	revision.Changes = diffSnapshots(snapshotTask(existing, oldTags, false), after)
+	revision.Snapshot = after
+	op := &entity.TaskOp{Kind: entity.TaskOpUpdate, Task: updated, Tags: tags, SetTags: setTags, Revision: revision, Then: next}
+	if err := s.applyTaskOp(ctx, op); err != nil {
 		log.Println("Error updating todo in repo:", err)
 		return err
 	}
@@ -124,28 +605,422 @@ func (s *TaskService) UpdateTodoByIDSvc(ctx context.Context, todo *models.Todo)
 	return nil
 }
 
-// We can do soft delete by adding boolean column is_deleted instead of directly deleting
-func (s *TaskService) DeleteTodoByIDSvc(ctx context.Context, taskID int, userID string) error {
-	task, err := s.repo.GetTodoByID(ctx, taskID)
+// checkTransition loads the workflow rules of the user and checks that they allow moving
+// existing to the status of updated
+func (s *TaskService) checkTransition(ctx context.Context, existing, updated *entity.Task) error {
+	transitions, err := s.statuses.ListTransitions(ctx, updated.UserID)
+	if err != nil {
+		log.Println("Error fetching status transitions from repo:", err)
+		return err
+	}
+	if len(transitions[existing.StatusID]) == 0 {
+		return nil
+	}
+
+	statuses, err := workflowStatuses(ctx, s.statuses, updated.UserID)
+	if err != nil {
+		return err
+	}
+	return checkTransition(statuses, transitions, existing, updated)
+}
+
+// GetStatusHistorySvc returns the status changes of a task of the user. Once the task is done it
+// also returns its lead time, from creation, and its cycle time, from the first move out of the
+// status it was created in, both until it last entered a terminal status.
+func (s *TaskService) GetStatusHistorySvc(ctx context.Context, taskID int, userID string) (*models.StatusHistory, error) {
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.ListStatusChanges(ctx, taskID)
+	if err != nil {
+		log.Println("Error fetching todo status history from repo:", err)
+		return nil, err
+	}
+
+	history := &models.StatusHistory{
+		TodoID:  task.ID,
+		Changes: make([]*models.StatusChange, 0, len(changes)),
+	}
+	var started, done time.Time
+	for i, change := range changes {
+		history.Changes = append(history.Changes, &models.StatusChange{
+			From:      change.FromStatus,
+			To:        change.ToStatus,
+			ChangedAt: change.ChangedAt,
+		})
+
+		// the first row is the status the task was created in
+		if i > 0 && started.IsZero() {
+			started = change.ChangedAt
+		}
+		// moving between terminal statuses does not complete the task again
+		if !change.Terminal {
+			done = time.Time{}
+		} else if done.IsZero() {
+			done = change.ChangedAt
+		}
+	}
+
+	if task.Terminal && !done.IsZero() {
+		lead := int64(done.Sub(task.CreatedAt).Seconds())
+		history.LeadTimeSeconds = &lead
+		if !started.IsZero() {
+			cycle := int64(done.Sub(started).Seconds())
+			history.CycleTimeSeconds = &cycle
+		}
+	}
+	return history, nil
+}
+
+func (s *TaskService) ListTrashSvc(ctx context.Context, userID string, limit, offset int) (*models.PaginatedTodos, error) {
+	tasks, total, err := s.repo.ListTrash(ctx, userID, limit, offset)
+	if err != nil {
+		log.Println("Error fetching trash from repo:", err)
+		return nil, err
+	}
+
+	var todos []*models.Todo
+	for _, task := range tasks {
+		todos = append(todos, toTodoModel(task))
+	}
+
+	return &models.PaginatedTodos{
+		TotalCount: &total,
+		Todos:      todos,
+	}, nil
+}
+
+func (s *TaskService) RestoreTodoSvc(ctx context.Context, taskID int, userID string) error {
+	task, err := s.trashedTask(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	// A subtask cannot come back under a parent that is still in the trash
+	if task.ParentID != nil {
+		if _, err := s.repo.GetTodoByID(ctx, int(*task.ParentID)); err != nil {
+			return errors.New("parent task is in the trash, restore it first")
+		}
+	}
+
+	tags, err := s.taskTags(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	revision := newRevision(entity.RevisionRestore, userID, snapshotTask(task, tags, true), snapshotTask(task, tags, false))
+	if err := s.applyTaskOp(ctx, &entity.TaskOp{Kind: entity.TaskOpRestore, Task: task, Revision: revision}); err != nil {
+		log.Println("Error restoring todo in repo:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *TaskService) PurgeTodoSvc(ctx context.Context, taskID int, userID string) error {
+	if _, err := s.trashedTask(ctx, taskID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.PurgeTodo(ctx, taskID); err != nil {
+		log.Println("Error purging todo in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// PurgeExpiredTrashSvc permanently deletes tasks that have been in the trash longer than retention
+func (s *TaskService) PurgeExpiredTrashSvc(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := s.repo.PurgeTrash(ctx, time.Now().Add(-retention))
+	if err != nil {
+		s.logger.Error("Error purging expired trash", zap.Error(err))
+		return 0, err
+	}
+	return purged, nil
+}
+
+// trashedTask fetches a task from the trash and makes sure it belongs to the user
+func (s *TaskService) trashedTask(ctx context.Context, taskID int, userID string) (*entity.Task, error) {
+	task, err := s.repo.GetTrashedTodoByID(ctx, taskID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w in trash", globals.ErrTaskNotFound)
+	}
+	if err != nil {
+		log.Println("Error fetching trashed todo from repo:", err)
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, globals.ErrTaskForbidden
+	}
+	return task, nil
+}
+
+// ownedTask fetches a task that is not in the trash and makes sure it belongs to the user
+func (s *TaskService) ownedTask(ctx context.Context, taskID int, userID string) (*entity.Task, error) {
+	return userTask(ctx, s.repo, taskID, userID)
+}
+
+// userTask fetches a task that is not in the trash and makes sure the user has access to it
+func userTask(ctx context.Context, tasks repo.TaskRepoInterface, taskID int, userID string) (*entity.Task, error) {
+	task, err := tasks.GetTodoByID(ctx, taskID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrTaskNotFound
+	}
 	if err != nil {
 		log.Println("Error fetching todo from repo:", err)
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, globals.ErrTaskForbidden
+	}
+	return task, nil
+}
+
+// statusFilter resolves the status a list is filtered on, 0 means every status
+func (s *TaskService) statusFilter(ctx context.Context, userID, name string) (int64, error) {
+	if name == "" || strings.EqualFold(name, globals.AllStatuses) {
+		return 0, nil
+	}
+	statuses, err := workflowStatuses(ctx, s.statuses, userID)
+	if err != nil {
+		return 0, err
+	}
+	status, err := findStatus(statuses, name)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid status filter: %s", globals.ErrValidation, name)
+	}
+	return status.ID, nil
+}
+
+// checkVersion fails with ErrPreconditionFailed when an If-Match version is given and the task moved on
+func checkVersion(task *entity.Task, ifMatch int64) error {
+	if ifMatch != 0 && task.Version != ifMatch {
+		return globals.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// nextOccurrence returns the op creating the task that follows a completed instance of a
+// repeating task, the rule moves onto it so it always lives on the latest instance. The op is
+// nil once the series has ended. tags are the tags of the completed instance.
+func nextOccurrence(done *entity.Task, statuses []*entity.Status, tags []string) (*entity.TaskOp, error) {
+	rule, err := rrule.Parse(done.Recurrence)
+	if err != nil {
+		log.Println("Error parsing stored recurrence rule:", err)
+		return nil, err
+	}
+
+	// Tasks without a due date repeat from the moment they were completed
+	current := done.DueAt
+	if current.IsZero() {
+		current = time.Now()
+	}
+
+	nextDue, ok := rule.Next(current, done.Occurrence)
+	if !ok {
+		// the series has ended
+		return nil, nil
+	}
+
+	next := &entity.Task{
+		UserID:      done.UserID,
+		ParentID:    done.ParentID,
+		ProjectID:   done.ProjectID,
+		Title:       done.Title,
+		Description: done.Description,
+		Priority:    done.Priority,
+		DueAt:       nextDue,
+		Recurrence:  done.Recurrence,
+		Occurrence:  done.Occurrence + 1,
+	}
+	setStatus(next, initialStatus(statuses))
+
+	revision := newRevision(entity.RevisionCreate, done.UserID, nil, snapshotTask(next, tags, false))
+	return &entity.TaskOp{Kind: entity.TaskOpCreate, Task: next, Tags: tags, SetTags: len(tags) > 0, Revision: revision}, nil
+}
+
+// UpdateRecurrenceSvc edits the repeat rule of a task, an empty rule cancels the recurrence
+func (s *TaskService) UpdateRecurrenceSvc(ctx context.Context, taskID int, userID, rule string, ifMatch int64) error {
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(task, ifMatch); err != nil {
 		return err
 	}
 
-	log.Printf("Attempting to delete task %d for user ID (from param): %s", taskID, userID)
+	recurrence, err := normalizeRecurrence(rule)
+	if err != nil {
+		return err
+	}
 
-	log.Println(task)
+	tags, err := s.taskTags(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	updated := *task
+	updated.Recurrence = recurrence
+	revision := newRevision(entity.RevisionUpdate, userID, snapshotTask(task, tags, false), snapshotTask(&updated, tags, false))
+	if err := s.applyTaskOp(ctx, &entity.TaskOp{Kind: entity.TaskOpUpdate, Task: &updated, Revision: revision}); err != nil {
+		log.Println("Error updating recurrence in repo:", err)
+		return err
+	}
+	return nil
+}
+
+// normalizeRecurrence validates a RRULE and returns it in canonical form, empty stays empty
+func normalizeRecurrence(rule string) (string, error) {
+	if strings.TrimSpace(rule) == "" {
+		return "", nil
+	}
+	parsed, err := rrule.Parse(rule)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+// DeleteTodoByIDSvc moves a task and all of its subtasks to the trash, a non-zero ifMatch must
+// match the stored version
+func (s *TaskService) DeleteTodoByIDSvc(ctx context.Context, taskID int, userID string, ifMatch int64) error {
+	log.Printf("Attempting to delete task %d for user ID (from param): %s", taskID, userID)
 
 	// Check ownership
-	if task == nil || task.UserID != userID {
-		return errors.New("task not found or unauthorized")
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(task, ifMatch); err != nil {
+		return err
 	}
 
-	// Perform delete
-	err = s.repo.DeleteTodo(ctx, taskID)
+	tags, err := s.taskTags(ctx, task.ID)
 	if err != nil {
+		return err
+	}
+
+	// subtasks trashed along with the task are covered by this revision
+	revision := newRevision(entity.RevisionDelete, userID, snapshotTask(task, tags, false), snapshotTask(task, tags, true))
+	if err := s.applyTaskOp(ctx, &entity.TaskOp{Kind: entity.TaskOpDelete, Task: task, Revision: revision}); err != nil {
 		log.Println("Error deleting todo in repo:", err)
 		return err
 	}
 	return nil
 }
+
+// attachTags loads the tags of the given todos and of their nested subtasks
+func (s *TaskService) attachTags(ctx context.Context, todos []*models.Todo) error {
+	nodes, ids := flattenTodos(todos)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tags, err := s.tags.ListTagsForTasks(ctx, ids)
+	if err != nil {
+		log.Println("Error fetching todo tags from repo:", err)
+		return err
+	}
+	for _, todo := range nodes {
+		todo.Tags = tags[todo.ID]
+	}
+	return nil
+}
+
+// attachDependencies sets the blockers of the given todos and of their nested subtasks, a todo
+// is flagged as blocked while one of its blockers is pending
+func (s *TaskService) attachDependencies(ctx context.Context, todos []*models.Todo) error {
+	nodes, ids := flattenTodos(todos)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	blockers, err := s.deps.ListBlockers(ctx, ids)
+	if err != nil {
+		log.Println("Error fetching todo blockers from repo:", err)
+		return err
+	}
+	for _, todo := range nodes {
+		for _, dep := range blockers[todo.ID] {
+			todo.BlockedBy = append(todo.BlockedBy, dep.BlockedByID)
+			if !dep.BlockerDone {
+				todo.Blocked = true
+			}
+		}
+	}
+	return nil
+}
+
+// attachCommentCounts sets the number of comments of the given todos and of their nested subtasks
+func (s *TaskService) attachCommentCounts(ctx context.Context, todos []*models.Todo) error {
+	nodes, ids := flattenTodos(todos)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	counts, err := s.comments.CountComments(ctx, ids)
+	if err != nil {
+		log.Println("Error counting todo comments in repo:", err)
+		return err
+	}
+	for _, todo := range nodes {
+		todo.Comments = counts[todo.ID]
+	}
+	return nil
+}
+
+// attachChecklistProgress sets the checklist progress of the given todos and of their nested subtasks
+func (s *TaskService) attachChecklistProgress(ctx context.Context, todos []*models.Todo) error {
+	nodes, ids := flattenTodos(todos)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	progress, err := s.checklist.CountItems(ctx, ids)
+	if err != nil {
+		log.Println("Error counting todo checklist items in repo:", err)
+		return err
+	}
+	for _, todo := range nodes {
+		if p, ok := progress[todo.ID]; ok {
+			todo.Checklist = &models.ChecklistProgress{Done: p.Done, Total: p.Total}
+		}
+	}
+	return nil
+}
+
+// flattenTodos lists the given todos and all their nested subtasks along with their ids
+func flattenTodos(todos []*models.Todo) ([]*models.Todo, []int64) {
+	var (
+		ids   []int64
+		nodes []*models.Todo
+	)
+	var walk func(list []*models.Todo)
+	walk = func(list []*models.Todo) {
+		for _, todo := range list {
+			ids = append(ids, todo.ID)
+			nodes = append(nodes, todo)
+			walk(todo.Subtasks)
+		}
+	}
+	walk(todos)
+	return nodes, ids
+}
+
+// toTodoModel maps a task entity to the todo model returned by the API
+func toTodoModel(task *entity.Task) *models.Todo {
+	return &models.Todo{
+		ID:          task.ID,
+		UserID:      task.UserID,
+		ParentID:    task.ParentID,
+		ProjectID:   task.ProjectID,
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    globals.TaskPriorityReverse[task.Priority],
+		Status:      task.Status,
+		DueAt:       task.DueAt,
+		Recurrence:  task.Recurrence,
+		Version:     task.Version,
+		Created:     task.CreatedAt,
+		Updated:     task.UpdatedAt,
+	}
+}
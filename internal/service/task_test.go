@@ -0,0 +1,202 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+func TestApplyPatch(t *testing.T) {
+	due := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	project := int64(5)
+	existing := &entity.Task{
+		ID:          1,
+		UserID:      "user-1",
+		ProjectID:   &project,
+		Title:       "Buy milk",
+		Description: "2 litres",
+		Priority:    2,
+		StatusID:    1,
+		Status:      "PENDING",
+		DueAt:       due,
+		Version:     3,
+	}
+	original := *existing
+	statuses := []*entity.Status{
+		{ID: 1, Name: "PENDING"},
+		{ID: 2, Name: "COMPLETED", IsTerminal: true},
+	}
+
+	tests := []struct {
+		name     string
+		patch    string
+		change   func(task *entity.Task)
+		wantTags []string
+	}{
+		{
+			name:  "empty patch changes nothing",
+			patch: `{}`,
+		},
+		{
+			name:   "only present members change",
+			patch:  `{"title": "Buy bread"}`,
+			change: func(task *entity.Task) { task.Title = "Buy bread" },
+		},
+		{
+			name:   "null clears the description",
+			patch:  `{"description": null}`,
+			change: func(task *entity.Task) { task.Description = "" },
+		},
+		{
+			name:   "null clears the due date",
+			patch:  `{"dueAt": null}`,
+			change: func(task *entity.Task) { task.DueAt = time.Time{} },
+		},
+		{
+			name:   "due date is replaced",
+			patch:  `{"dueAt": "2024-04-01T18:30:00Z"}`,
+			change: func(task *entity.Task) { task.DueAt = time.Date(2024, 4, 1, 18, 30, 0, 0, time.UTC) },
+		},
+		{
+			name:  "priority and status",
+			patch: `{"priority": "HIGH", "status": "completed"}`,
+			change: func(task *entity.Task) {
+				task.Priority = 3
+				task.StatusID, task.Status, task.Terminal = 2, "COMPLETED", true
+			},
+		},
+		{
+			name:  "project is moved",
+			patch: `{"project_id": 9}`,
+			change: func(task *entity.Task) {
+				moved := int64(9)
+				task.ProjectID = &moved
+			},
+		},
+		{
+			name:     "arrays are replaced as a whole",
+			patch:    `{"tags": ["Home", " home ", "Errands"]}`,
+			wantTags: []string{"home", "errands"},
+		},
+		{
+			name:     "null clears the tags",
+			patch:    `{"tags": null}`,
+			wantTags: []string{},
+		},
+		{
+			name:     "empty array clears the tags",
+			patch:    `{"tags": []}`,
+			wantTags: []string{},
+		},
+		{
+			name:   "unknown members are ignored, nested ones too",
+			patch:  `{"title": "Buy bread", "meta": {"source": {"app": "web"}}, "version": 1}`,
+			change: func(task *entity.Task) { task.Title = "Buy bread" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch models.TodoPatch
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("patch %s does not decode: %v", tt.patch, err)
+			}
+
+			updated, tags, err := applyPatch(existing, &patch, statuses)
+			if err != nil {
+				t.Fatalf("applyPatch error: %v", err)
+			}
+
+			want := original
+			if tt.change != nil {
+				tt.change(&want)
+			}
+			if !reflect.DeepEqual(*updated, want) {
+				t.Errorf("applyPatch = %+v, want %+v", *updated, want)
+			}
+			if !reflect.DeepEqual(tags, tt.wantTags) {
+				t.Errorf("tags = %#v, want %#v", tags, tt.wantTags)
+			}
+			if !reflect.DeepEqual(*existing, original) {
+				t.Errorf("applyPatch modified the existing task: %+v", *existing)
+			}
+		})
+	}
+}
+
+func TestApplyPatchRejectsInvalidMembers(t *testing.T) {
+	existing := &entity.Task{ID: 1, Title: "Buy milk", Priority: 2, StatusID: 1, Status: "PENDING"}
+	statuses := []*entity.Status{{ID: 1, Name: "PENDING"}, {ID: 2, Name: "COMPLETED", IsTerminal: true}}
+
+	for _, doc := range []string{
+		`{"title": null}`,
+		`{"title": "   "}`,
+		`{"priority": null}`,
+		`{"priority": "URGENT"}`,
+		`{"status": null}`,
+		`{"status": "ARCHIVED"}`,
+		`{"project_id": null}`,
+	} {
+		var patch models.TodoPatch
+		if err := json.Unmarshal([]byte(doc), &patch); err != nil {
+			t.Fatalf("patch %s does not decode: %v", doc, err)
+		}
+		if _, _, err := applyPatch(existing, &patch, statuses); !errors.Is(err, globals.ErrValidation) {
+			t.Errorf("applyPatch(%s) error = %v, want ErrValidation", doc, err)
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	project := int64(5)
+	done := &entity.Task{
+		ID:         1,
+		UserID:     "user-1",
+		ProjectID:  &project,
+		Title:      "Water plants",
+		Priority:   2,
+		StatusID:   2,
+		Status:     "COMPLETED",
+		Terminal:   true,
+		DueAt:      time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC),
+		Recurrence: "FREQ=DAILY;COUNT=2",
+		Occurrence: 1,
+		Version:    4,
+	}
+	statuses := []*entity.Status{{ID: 1, Name: "PENDING"}, {ID: 2, Name: "COMPLETED", IsTerminal: true}}
+
+	op, err := nextOccurrence(done, statuses, []string{"home"})
+	if err != nil {
+		t.Fatalf("nextOccurrence error: %v", err)
+	}
+	if op == nil || op.Kind != entity.TaskOpCreate || op.Revision == nil {
+		t.Fatalf("nextOccurrence = %+v, want a create op with its revision", op)
+	}
+	want := entity.Task{
+		UserID:     "user-1",
+		ProjectID:  &project,
+		Title:      "Water plants",
+		Priority:   2,
+		StatusID:   1,
+		Status:     "PENDING",
+		DueAt:      time.Date(2024, 3, 9, 9, 0, 0, 0, time.UTC),
+		Recurrence: "FREQ=DAILY;COUNT=2",
+		Occurrence: 2,
+	}
+	if !reflect.DeepEqual(*op.Task, want) {
+		t.Errorf("next task = %+v, want %+v", *op.Task, want)
+	}
+	if !op.SetTags || !reflect.DeepEqual(op.Tags, []string{"home"}) {
+		t.Errorf("next tags = %v (set %v), want [home]", op.Tags, op.SetTags)
+	}
+
+	last := *op.Task
+	if op, err = nextOccurrence(&last, statuses, nil); err != nil || op != nil {
+		t.Errorf("nextOccurrence after the last instance = %+v, %v, want nil", op, err)
+	}
+}
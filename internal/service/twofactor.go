@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/jwt"
+	"github.com/shivarajshanthaiah/todo-app/internal/models"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+	"github.com/shivarajshanthaiah/todo-app/pkg/totp"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes handed out when 2FA is turned on
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the number of characters of a recovery code, 80 random bits
+	recoveryCodeLength = 16
+	// recoveryCodeAlphabet avoids characters that are easily mixed up, like 0/o and 1/l
+	recoveryCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+	// totpSkew is the number of time steps a code may be off either way
+	totpSkew = 1
+	// maxChallengeAttempts is the number of wrong codes after which a login challenge is dropped
+	maxChallengeAttempts = 5
+	// loginWrongCode is the reason recorded for a login that failed on the second factor
+	loginWrongCode = "wrong 2fa code"
+)
+
+// loginChallenge is a login waiting for its second factor, stored in redis. The codes tried
+// against it are counted under the same key with a ":attempts" suffix.
+type loginChallenge struct {
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EnrollTwoFactorSvc starts turning on two-factor authentication with a new secret. It stays
+// off until a code of the secret is confirmed, enrolling again replaces the secret.
+func (s *UserService) EnrollTwoFactorSvc(ctx context.Context, userID string) (*models.TwoFactorEnrollment, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, globals.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.SetTOTPSecret(ctx, userID, secret)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrTwoFactorEnabled
+	}
+	if err != nil {
+		log.Println("Error storing totp secret in repo:", err)
+		return nil, err
+	}
+
+	return &models.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.cnfg.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactorSvc turns on two-factor authentication once the user proves their app has the
+// enrolled secret, it returns the recovery codes which are not shown again
+func (s *UserService) ConfirmTwoFactorSvc(ctx context.Context, userID, code string) (*models.RecoveryCodes, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, globals.ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, fmt.Errorf("%w: enroll in two-factor authentication before confirming it", globals.ErrValidation)
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, globals.ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	err = s.repo.EnableTOTP(ctx, userID, step, hashes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, globals.ErrTwoFactorEnabled
+	}
+	if err != nil {
+		log.Println("Error enabling totp in repo:", err)
+		return nil, err
+	}
+	s.forgetCachedUser(userID)
+	return &models.RecoveryCodes{Codes: codes}, nil
+}
+
+// DisableTwoFactorSvc turns off two-factor authentication after checking the account password
+func (s *UserService) DisableTwoFactorSvc(ctx context.Context, userID, password string) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return err
+	}
+	if user.TOTPEnabledAt == nil {
+		return globals.ErrTwoFactorNotEnabled
+	}
+
+	// only the lookup by email reads the password hash
+	withPassword, err := s.repo.GetUserByEmail(ctx, user.Email)
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return err
+	}
+	if !jwt.CheckPassword(password, withPassword.Password) {
+		return globals.ErrIncorrectPassword
+	}
+
+	if err := s.repo.DisableTOTP(ctx, userID); err != nil {
+		log.Println("Error disabling totp in repo:", err)
+		return err
+	}
+	s.forgetCachedUser(userID)
+	return nil
+}
+
+// LoginTwoFactorSvc completes a login that is waiting for its second factor, the code is either
+// from the authenticator app or one of the recovery codes
+func (s *UserService) LoginTwoFactorSvc(ctx context.Context, req *models.TwoFactorLogin, client *models.Client) (*models.TokenPair, error) {
+	key := globals.LoginChallengePrefix + jwt.HashOpaqueToken(req.ChallengeToken)
+	data, err := s.redis.GetFromRedis(key)
+	if err == redis.Nil {
+		return nil, globals.ErrInvalidLoginChallenge
+	}
+	if err != nil {
+		log.Printf("Error accessing Redis: %v", err)
+		return nil, err
+	}
+	var challenge loginChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		log.Printf("Error unmarshalling login challenge: %v", err)
+		return nil, globals.ErrInvalidLoginChallenge
+	}
+
+	// counted before the code is checked, so parallel guesses cannot share one attempt
+	attemptsKey := key + ":attempts"
+	attempts, err := s.redis.IncrInRedis(attemptsKey, time.Until(challenge.ExpiresAt))
+	if err != nil {
+		log.Printf("Error counting login challenge attempts: %v", err)
+		return nil, err
+	}
+	if attempts > maxChallengeAttempts {
+		s.dropLoginChallenge(key)
+		return nil, globals.ErrInvalidLoginChallenge
+	}
+
+	user, err := s.repo.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return nil, err
+	}
+	if user.TOTPEnabledAt == nil {
+		// turned off in the meantime, the challenge no longer applies
+		s.dropLoginChallenge(key)
+		return nil, globals.ErrInvalidLoginChallenge
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.recordLoginAttempt(ctx, user.ID, user.Email, loginWrongCode, client)
+		if attempts >= maxChallengeAttempts {
+			s.dropLoginChallenge(key)
+		}
+		return nil, globals.ErrInvalidTwoFactorCode
+	}
+
+	if err := s.redis.DeleteFromRedis(key); err != nil {
+		log.Printf("Error deleting login challenge: %v", err)
+		return nil, err
+	}
+	if err := s.redis.DeleteFromRedis(attemptsKey); err != nil {
+		log.Printf("Error deleting login challenge attempts: %v", err)
+	}
+	return s.completeLogin(ctx, user, client)
+}
+
+// dropLoginChallenge removes a login challenge together with its attempt counter
+func (s *UserService) dropLoginChallenge(key string) {
+	for _, k := range []string{key, key + ":attempts"} {
+		if err := s.redis.DeleteFromRedis(k); err != nil {
+			log.Printf("Error deleting login challenge: %v", err)
+		}
+	}
+}
+
+// startLoginChallenge holds a login whose password was right until the second factor is given
+func (s *UserService) startLoginChallenge(user *entity.User) (*models.LoginChallenge, error) {
+	token, hash, err := jwt.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	ttl := time.Duration(s.cnfg.TwoFactorChallengeMinutes) * time.Minute
+	data, err := json.Marshal(loginChallenge{
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.SetDataInRedis(globals.LoginChallengePrefix+hash, data, ttl); err != nil {
+		log.Printf("Error storing login challenge: %v", err)
+		return nil, err
+	}
+	return &models.LoginChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int64(ttl.Seconds()),
+	}, nil
+}
+
+// checkSecondFactor reports whether the code is a TOTP code or an unused recovery code of the
+// user, either is used up by a successful check
+func (s *UserService) checkSecondFactor(ctx context.Context, user *entity.User, code string) (bool, error) {
+	if step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew); ok {
+		err := s.repo.AcceptTOTPStep(ctx, user.ID, step)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the code was used before
+			return false, nil
+		}
+		if err != nil {
+			log.Println("Error accepting totp code in repo:", err)
+			return false, err
+		}
+		return true, nil
+	}
+
+	err := s.repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		log.Println("Error using recovery code in repo:", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// newRecoveryCode returns a random recovery code grouped like abcd-efgh-ijkm-npqr
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	var code strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		// the alphabet has 32 characters, so every byte maps without bias
+		code.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+	}
+	return code.String(), nil
+}
+
+// hashRecoveryCode hashes a recovery code the way it is stored, ignoring case, dashes and spaces
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	return jwt.HashOpaqueToken(normalized)
+}
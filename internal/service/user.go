@@ -5,33 +5,45 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/shivarajshanthaiah/todo-app/configs"
+	"github.com/shivarajshanthaiah/todo-app/internal/clients/mailer"
 	redisCl "github.com/shivarajshanthaiah/todo-app/internal/clients/redis"
 	"github.com/shivarajshanthaiah/todo-app/internal/jwt"
 	"github.com/shivarajshanthaiah/todo-app/internal/models"
 	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
 	repo "github.com/shivarajshanthaiah/todo-app/internal/repo/interfaces"
 	service "github.com/shivarajshanthaiah/todo-app/internal/service/interfaces"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
 	"go.uber.org/zap"
 )
 
 type UserService struct {
-	repo   repo.UserRepoInterface
-	cnfg   *configs.Config
-	redis  *redisCl.RedisService
-	logger *zap.Logger
+	repo     repo.UserRepoInterface
+	projects repo.ProjectRepoInterface
+	tokens   repo.TokenRepoInterface
+	sessions repo.SessionRepoInterface
+	mailer   mailer.Mailer
+	cnfg     *configs.Config
+	redis    *redisCl.RedisService
+	logger   *zap.Logger
 }
 
-func NewUserService(repo repo.UserRepoInterface, cnfg *configs.Config, redis *redisCl.RedisService, logger *zap.Logger) service.UserServiceInterface {
+func NewUserService(repo repo.UserRepoInterface, projects repo.ProjectRepoInterface, tokens repo.TokenRepoInterface, sessions repo.SessionRepoInterface, mailer mailer.Mailer, cnfg *configs.Config, redis *redisCl.RedisService, logger *zap.Logger) service.UserServiceInterface {
 	return &UserService{
-		repo:   repo,
-		cnfg:   cnfg,
-		redis:  redis,
-		logger: logger,
+		repo:     repo,
+		projects: projects,
+		tokens:   tokens,
+		sessions: sessions,
+		mailer:   mailer,
+		cnfg:     cnfg,
+		redis:    redis,
+		logger:   logger,
 	}
 }
 
@@ -42,6 +54,11 @@ func (s *UserService) UserSignUpSvc(ctx context.Context, user *models.User) erro
 		return err
 	}
 
+	user.Email = strings.TrimSpace(user.Email)
+	if err := validateEmail(user.Email); err != nil {
+		return err
+	}
+
 	hashedPassword, err := jwt.HashPassword(user.Password)
 	if err != nil {
 		log.Println("Error while hashing the password", err)
@@ -59,34 +76,78 @@ func (s *UserService) UserSignUpSvc(ctx context.Context, user *models.User) erro
 		log.Println("Error creating user in repo:", err)
 		return err
 	}
+
+	// Every user starts with an Inbox project
+	if _, err := defaultProject(ctx, s.projects, entityUser.ID); err != nil {
+		return err
+	}
+
+	// the account exists either way, when the mail fails it can be sent again through the
+	// resend endpoint, otherwise the first resend waits as long as any other
+	if err := s.sendVerificationEmail(ctx, entityUser); err == nil {
+		_, _ = s.redis.SetIfAbsentInRedis(globals.EmailVerifyResendPrefix+strings.ToLower(entityUser.Email), []byte("1"), s.resendCooldown())
+	}
 	return nil
 }
 
-func (s *UserService) UserLoginSvc(ctx context.Context, login *models.Login) (string, error) {
+// UserLoginSvc checks the credentials of a login. Accounts with two-factor authentication get a
+// challenge to complete with LoginTwoFactorSvc instead of the token pair.
+func (s *UserService) UserLoginSvc(ctx context.Context, login *models.Login, client *models.Client) (*models.TokenPair, *models.LoginChallenge, error) {
+	// an unknown email and a wrong password get the same error, so the response does not
+	// tell whether an account exists, only the recorded attempt keeps them apart
 	user, err := s.repo.GetUserByEmail(ctx, login.Email)
 	if err != nil {
 		log.Println("Error while fetching the user from DB", err)
-		return "", err
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.recordLoginAttempt(ctx, "", login.Email, loginUnknownEmail, client)
+			return nil, nil, globals.ErrIncorrectPassword
+		}
+		return nil, nil, err
 	}
 
-	log.Printf("User fetched from DB: ID=%s, Email=%s, HashedPassword=%s", user.ID, user.Email, user.Password)
-
 	match := jwt.CheckPassword(login.Password, user.Password)
-	log.Printf("Password match result: %v (plain=%s, hashed=%s)", match, login.Password, user.Password)
-
 	if !match {
 		log.Printf("Incorrect password for user %s", user.Email)
-		return "", errors.New("password incorrect")
+		s.recordLoginAttempt(ctx, user.ID, user.Email, loginWrongPassword, client)
+		return nil, nil, globals.ErrIncorrectPassword
+	}
+
+	if s.cnfg.RequireVerifiedLogin && user.EmailVerifiedAt == nil {
+		s.recordLoginAttempt(ctx, user.ID, user.Email, loginEmailNotVerified, client)
+		return nil, nil, globals.ErrEmailNotVerified
 	}
 
-	token, err := jwt.GenerateToken(s.cnfg.SECRETKEY, user.Email, user.ID)
+	if user.TOTPEnabledAt != nil {
+		challenge, err := s.startLoginChallenge(user)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	tokens, err := s.completeLogin(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, nil, nil
+}
+
+// completeLogin starts a session for a user who passed every login check
+func (s *UserService) completeLogin(ctx context.Context, user *entity.User, client *models.Client) (*models.TokenPair, error) {
+	// every login is a new session with its own family of refresh tokens
+	session, err := s.startSession(ctx, user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.issueTokens(ctx, user, session.ID, nil)
 	if err != nil {
 		log.Printf("Error generating token for user %s: %v", user.Email, err)
-		return "", err
+		return nil, err
 	}
 
+	s.recordLoginAttempt(ctx, user.ID, user.Email, "", client)
 	log.Printf("Login successful for user: %s", user.Email)
-	return token, nil
+	return tokens, nil
 }
 
 func (s *UserService) GetUserByIDSvc(ctx context.Context, userID string) (*models.User, string, error) {
@@ -101,9 +162,11 @@ func (s *UserService) GetUserByIDSvc(ctx context.Context, userID string) (*model
 			log.Printf("Error unmarshalling cached user data: %v", err)
 		} else {
 			return &models.User{
-				ID:       user.ID,
-				UserName: user.UserName,
-				Email:    user.Email,
+				ID:               user.ID,
+				UserName:         user.UserName,
+				Email:            user.Email,
+				EmailVerified:    user.EmailVerified,
+				TwoFactorEnabled: user.TwoFactorEnabled,
 			}, "fethed from cache", nil
 		}
 	} else if err != redis.Nil {
@@ -124,10 +187,13 @@ func (s *UserService) GetUserByIDSvc(ctx context.Context, userID string) (*model
 		UserName: user.UserName,
 		Email:    user.Email,
 		Password: "", // don’t expose password
+
+		EmailVerified:    user.EmailVerifiedAt != nil,
+		TwoFactorEnabled: user.TOTPEnabledAt != nil,
 	}
 
 	// Cache the retrieved user data for future requests
-	userData, err := json.Marshal(user)
+	userData, err := json.Marshal(userModel)
 	if err == nil {
 		// seting 2 mins cachefor testing
 		_ = s.redis.SetDataInRedis(cacheKey, userData, time.Minute*2)
@@ -137,3 +203,10 @@ func (s *UserService) GetUserByIDSvc(ctx context.Context, userID string) (*model
 
 	return userModel, "fetched from DB", nil
 }
+
+// forgetCachedUser drops the cached profile of a user after it changed
+func (s *UserService) forgetCachedUser(userID string) {
+	if err := s.redis.DeleteFromRedis("user_" + userID); err != nil {
+		log.Printf("Error clearing cached user %s: %v", userID, err)
+	}
+}
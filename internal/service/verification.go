@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shivarajshanthaiah/todo-app/internal/clients/mailer"
+	"github.com/shivarajshanthaiah/todo-app/internal/jwt"
+	"github.com/shivarajshanthaiah/todo-app/internal/repo/entity"
+	"github.com/shivarajshanthaiah/todo-app/pkg/globals"
+)
+
+// VerifyEmailSvc marks the email of the user a verification token was sent to as verified
+func (s *UserService) VerifyEmailSvc(ctx context.Context, token string) error {
+	userID, err := s.tokens.VerifyEmail(ctx, jwt.HashOpaqueToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return globals.ErrInvalidVerificationToken
+	}
+	if err != nil {
+		log.Println("Error verifying email in repo:", err)
+		return err
+	}
+
+	// the cached profile still says unverified
+	s.forgetCachedUser(userID)
+	return nil
+}
+
+// ResendVerificationSvc mails a new verification token to an unverified account. Like the
+// password reset it does not tell whether the email belongs to an account, and it can be used
+// once per EMAIL_VERIFY_RESEND_SECONDS for an email.
+func (s *UserService) ResendVerificationSvc(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	allowed, err := s.redis.SetIfAbsentInRedis(globals.EmailVerifyResendPrefix+strings.ToLower(email), []byte("1"), s.resendCooldown())
+	if err != nil {
+		log.Println("Error throttling verification email in redis:", err)
+		return err
+	}
+	if !allowed {
+		return globals.ErrTooManyRequests
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return s.sendVerificationEmail(ctx, user)
+}
+
+// EnsureEmailVerifiedSvc returns ErrEmailNotVerified when changing tasks needs a verified email
+// and the user has not verified theirs
+func (s *UserService) EnsureEmailVerifiedSvc(ctx context.Context, userID string) error {
+	if !s.cnfg.RequireVerifiedTasks {
+		return nil
+	}
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Println("Error while fetching the user from DB", err)
+		return err
+	}
+	if user.EmailVerifiedAt == nil {
+		return globals.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// sendVerificationEmail mails a new verification token to the user, the ones sent before stop
+// working
+func (s *UserService) sendVerificationEmail(ctx context.Context, user *entity.User) error {
+	token, hash, err := jwt.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(s.cnfg.EmailVerifyHours) * time.Hour
+	if err := s.tokens.CreateEmailVerification(ctx, user.ID, hash, time.Now().Add(ttl)); err != nil {
+		log.Println("Error creating email verification in repo:", err)
+		return err
+	}
+
+	body := fmt.Sprintf("Welcome %s,\n\nplease confirm your email address within %d hours with this code:\n\n%s\n",
+		user.UserName, s.cnfg.EmailVerifyHours, token)
+	if s.cnfg.AppURL != "" {
+		body += fmt.Sprintf("\nOr open %s/verify-email?token=%s\n", strings.TrimSuffix(s.cnfg.AppURL, "/"), url.QueryEscape(token))
+	}
+	body += "\nIf you did not sign up you can ignore this email.\n"
+
+	s.sendMail(&mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body:    body,
+	})
+	return nil
+}
+
+// resendCooldown is how long to wait between two verification or password reset emails to the
+// same address
+func (s *UserService) resendCooldown() time.Duration {
+	return time.Duration(s.cnfg.EmailVerifyResendSeconds) * time.Second
+}
+
+// validateEmail accepts a bare email address like user@example.com
+func validateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("%w: %q is not a valid email address", globals.ErrValidation, email)
+	}
+	return nil
+}
@@ -24,4 +24,317 @@ CREATE TABLE tasks (
   due_at TIMESTAMP
 );
 
-CREATE INDEX idx_tasks_user_id ON tasks (user_id); -- to make the query excecute faster
\ No newline at end of file
+CREATE INDEX idx_tasks_user_id ON tasks (user_id); -- to make the query excecute faster
+
+-- Subtasks reference their parent task, deleting a parent deletes its subtasks. References need
+-- a unique id, which the table above does not declare.
+ALTER TABLE tasks ADD PRIMARY KEY (id);
+
+ALTER TABLE tasks ADD COLUMN parent_id INT REFERENCES tasks(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_tasks_parent_id ON tasks (parent_id);
+
+
+-- Projects group tasks into lists, every user has one default Inbox project
+CREATE TABLE projects (
+  id SERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  name VARCHAR(119) NOT NULL,
+  is_default BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_projects_user_name ON projects (user_id, lower(name));
+CREATE UNIQUE INDEX idx_projects_user_default ON projects (user_id) WHERE is_default;
+
+ALTER TABLE tasks ADD COLUMN project_id INT REFERENCES projects(id);
+
+CREATE INDEX idx_tasks_project_id ON tasks (project_id);
+
+
+-- Tags are stored lower cased, one row per user and name
+CREATE TABLE tags (
+  id SERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  name VARCHAR(63) NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  UNIQUE (user_id, name)
+);
+
+CREATE TABLE task_tags (
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX idx_task_tags_tag_id ON task_tags (tag_id);
+
+
+-- Repeating tasks keep their RRULE on the latest instance of the series
+ALTER TABLE tasks ADD COLUMN recurrence TEXT;
+ALTER TABLE tasks ADD COLUMN occurrence INT NOT NULL DEFAULT 1;
+
+
+-- Deleted tasks stay in the trash until restored or purged
+ALTER TABLE tasks ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_tasks_deleted_at ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+
+-- Full-text search over title (weight A) and description (weight B)
+ALTER TABLE tasks ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+  setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+
+-- Tasks without a due date used to be stored as the zero time, they are NULL now
+UPDATE tasks SET due_at = NULL WHERE due_at = '0001-01-01 00:00:00';
+
+CREATE INDEX idx_tasks_user_due_at ON tasks (user_id, due_at);
+
+
+-- Every change bumps the version, it backs the ETag / If-Match checks
+ALTER TABLE tasks ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+
+-- Blocked-by relationships, task_id cannot start until blocked_by_id is done
+CREATE TABLE task_dependencies (
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  blocked_by_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  created_at TIMESTAMP DEFAULT now(),
+  PRIMARY KEY (task_id, blocked_by_id),
+  CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX idx_task_dependencies_blocked_by_id ON task_dependencies (blocked_by_id);
+
+
+-- Workflow statuses per user, tasks in a terminal status count as done
+CREATE TABLE statuses (
+  id SERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  name VARCHAR(63) NOT NULL,
+  position INT NOT NULL DEFAULT 0,
+  color VARCHAR(7) NOT NULL DEFAULT '#9e9e9e',
+  is_terminal BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_statuses_user_name ON statuses (user_id, upper(name));
+
+-- Every existing user starts with the two statuses that used to be hard-coded
+INSERT INTO statuses (user_id, name, position, color, is_terminal)
+SELECT u.id, d.name, d.position, d.color, d.is_terminal
+FROM (SELECT id FROM users UNION SELECT user_id FROM tasks) u
+CROSS JOIN (VALUES ('PENDING', 1, '#f0ad4e', false), ('COMPLETED', 2, '#5cb85c', true)) AS d (name, position, color, is_terminal)
+WHERE NOT EXISTS (SELECT 1 FROM statuses s WHERE s.user_id = u.id);
+
+-- The old integer status (1 = PENDING, 2 = COMPLETED) is mapped to the user's status rows.
+-- tasks.status is no longer read and only kept for rolling back.
+ALTER TABLE tasks ADD COLUMN status_id INT REFERENCES statuses(id);
+
+UPDATE tasks t SET status_id = s.id FROM statuses s
+WHERE s.user_id = t.user_id
+  AND s.name = CASE t.status WHEN 2 THEN 'COMPLETED' ELSE 'PENDING' END;
+
+ALTER TABLE tasks ALTER COLUMN status_id SET NOT NULL;
+
+CREATE INDEX idx_tasks_status_id ON tasks (status_id);
+
+
+-- Allowed next statuses, a status without rows here can move to any other status
+CREATE TABLE status_transitions (
+  from_status_id INT NOT NULL REFERENCES statuses(id) ON DELETE CASCADE,
+  to_status_id INT NOT NULL REFERENCES statuses(id) ON DELETE CASCADE,
+  PRIMARY KEY (from_status_id, to_status_id),
+  CHECK (from_status_id <> to_status_id)
+);
+
+-- Every status a task entered, from_status_id is NULL for the status it was created in.
+-- Lead and cycle times are computed from these rows.
+CREATE TABLE task_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  from_status_id INT REFERENCES statuses(id) ON DELETE SET NULL,
+  to_status_id INT REFERENCES statuses(id) ON DELETE SET NULL,
+  changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_task_status_history_task_id ON task_status_history (task_id, changed_at);
+
+-- Existing tasks get the status they are in now as their starting point
+INSERT INTO task_status_history (task_id, to_status_id, changed_at)
+SELECT id, status_id, COALESCE(created_at, now()) FROM tasks;
+
+
+-- Revision log of tasks. changes maps each changed field to {"old", "new"}, snapshot holds
+-- every tracked field after the change and is what a revert goes back to. Tasks created
+-- before this table start their history at their next change.
+CREATE TABLE task_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  revision INT NOT NULL,
+  action VARCHAR(15) NOT NULL,
+  actor_id VARCHAR(63) NOT NULL,
+  reverted_to INT,
+  changes JSONB NOT NULL DEFAULT '{}',
+  snapshot JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE (task_id, revision)
+);
+
+
+-- Comments on tasks, removed together with their task
+CREATE TABLE comments (
+  id SERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  user_id VARCHAR(63) NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_comments_task_id ON comments (task_id, created_at);
+
+
+-- Files attached to tasks, the bytes live in the configured storage under storage_key. Rows
+-- outlive a purged task with task_id cleared until the sweeper removes their bytes.
+CREATE TABLE attachments (
+  id SERIAL PRIMARY KEY,
+  task_id INT REFERENCES tasks(id) ON DELETE SET NULL,
+  user_id VARCHAR(63) NOT NULL,
+  filename VARCHAR(255) NOT NULL,
+  content_type VARCHAR(127) NOT NULL,
+  size BIGINT NOT NULL,
+  storage_key VARCHAR(255) NOT NULL UNIQUE,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_attachments_task_id ON attachments (task_id, created_at);
+CREATE INDEX idx_attachments_user_id ON attachments (user_id);
+
+
+-- Ordered checklist items of a task, removed together with their task
+CREATE TABLE checklist_items (
+  id SERIAL PRIMARY KEY,
+  task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  text VARCHAR(500) NOT NULL,
+  done BOOLEAN NOT NULL DEFAULT false,
+  position INT NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_checklist_items_task_id ON checklist_items (task_id, position);
+
+
+-- Refresh tokens, stored as SHA-256 hashes. Every login starts a family that each refresh
+-- rotates to a new token, presenting a used token again revokes the whole family. access_jti
+-- names the access token issued together with the token so it can be denylisted on revoke.
+CREATE TABLE refresh_tokens (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  family_id VARCHAR(63) NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  access_jti VARCHAR(63) NOT NULL,
+  access_expires_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  used_at TIMESTAMP,
+  revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE INDEX idx_refresh_tokens_access_jti ON refresh_tokens (access_jti);
+
+
+-- Logins of users, one per refresh token family, with the device they were made from.
+-- last_seen_at and expires_at move forward on every refresh.
+CREATE TABLE sessions (
+  id VARCHAR(63) PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  user_agent VARCHAR(255) NOT NULL DEFAULT '',
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMP NOT NULL DEFAULT now(),
+  expires_at TIMESTAMP NOT NULL,
+  revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions (user_id, last_seen_at);
+
+-- Logins made before sessions were recorded
+INSERT INTO sessions (id, user_id, created_at, last_seen_at, expires_at)
+SELECT family_id, user_id, MIN(created_at), MAX(created_at), MAX(expires_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
+
+-- Every login attempt, user_id is empty when the email matched no user
+CREATE TABLE login_attempts (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63),
+  email VARCHAR(63) NOT NULL,
+  success BOOLEAN NOT NULL,
+  reason VARCHAR(63) NOT NULL DEFAULT '',
+  user_agent VARCHAR(255) NOT NULL DEFAULT '',
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_login_attempts_user_id ON login_attempts (user_id, created_at);
+
+
+-- Password reset tokens, stored as SHA-256 hashes. A token works once and only until it expires.
+CREATE TABLE password_resets (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_password_resets_user_id ON password_resets (user_id);
+
+
+-- Email verification. Accounts that existed before verification was introduced count as
+-- verified, new accounts start unverified until a mailed token is used.
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP DEFAULT now();
+ALTER TABLE users ALTER COLUMN email_verified_at DROP DEFAULT;
+
+CREATE TABLE email_verifications (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_email_verifications_user_id ON email_verifications (user_id);
+
+
+-- Two-factor authentication. totp_secret is set on enrollment and only counts once
+-- totp_enabled_at is set, totp_last_step keeps a code from being accepted twice. Recovery codes
+-- are stored as SHA-256 hashes and work once each.
+ALTER TABLE users ADD COLUMN totp_secret TEXT;
+ALTER TABLE users ADD COLUMN totp_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_codes (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(63) NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
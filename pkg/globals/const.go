@@ -6,9 +6,30 @@ const (
 	MEDIUM = "MEDIUM"
 	HIGH   = "HIGH"
 
-	//task status
+	// statuses every user starts with, the workflow itself lives in the statuses table
 	PENDING   = "PENDING"
 	COMPLETED = "COMPLETED"
+
+	// name the status list accepts to not filter on status
+	AllStatuses = "ALL"
+
+	// MaxPageLimit is the largest page size the list endpoints return, larger limits are lowered to it
+	MaxPageLimit = 100
+
+	// name of the project every user gets on signup
+	DefaultProject = "Inbox"
+
+	// prefix of the redis keys of revoked access tokens, followed by their jti
+	TokenDenylistPrefix = "jwt_denylist:"
+
+	// prefix of the redis keys throttling verification emails, followed by the lowercased email
+	EmailVerifyResendPrefix = "email_verify_resend:"
+
+	// prefix of the redis keys throttling password reset emails, followed by the lowercased email
+	PasswordResetResendPrefix = "password_reset_resend:"
+
+	// prefix of the redis keys of pending two-factor logins, followed by the hashed challenge token
+	LoginChallengePrefix = "login_2fa:"
 )
 
 var TaskPriority = map[string]int{
@@ -17,9 +38,10 @@ var TaskPriority = map[string]int{
 	HIGH:   3,
 }
 
-var TaskStatus = map[string]int{
-	PENDING:   1,
-	COMPLETED: 2,
+// DefaultStatusColors are the colors of the statuses seeded for every user
+var DefaultStatusColors = map[string]string{
+	PENDING:   "#f0ad4e",
+	COMPLETED: "#5cb85c",
 }
 
 var TaskPriorityReverse = map[int]string{
@@ -28,7 +50,11 @@ var TaskPriorityReverse = map[int]string{
 	3: HIGH,
 }
 
-var TaskStatusReverse = map[int]string{
-	1: PENDING,
-	2: COMPLETED,
+// TaskSortFields are the fields a todo list can be sorted by
+var TaskSortFields = map[string]bool{
+	"due_at":     true,
+	"priority":   true,
+	"title":      true,
+	"created_at": true,
+	"updated_at": true,
 }
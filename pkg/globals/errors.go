@@ -0,0 +1,119 @@
+package globals
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by the services that handlers translate into HTTP status codes
+var (
+	ErrTaskNotFound  = errors.New("task not found")
+	ErrTaskForbidden = errors.New("task belongs to another user")
+	ErrValidation    = errors.New("invalid request")
+
+	// ErrProjectNotFound is returned for a project that does not exist or belongs to another user
+	ErrProjectNotFound = errors.New("project not found")
+
+	// ErrTagNotFound is returned for a tag that does not exist or belongs to another user
+	ErrTagNotFound = errors.New("tag not found")
+
+	// ErrTaskBlocked is returned when completing a task that still waits on pending blockers
+	ErrTaskBlocked = errors.New("task is blocked by pending tasks")
+
+	// ErrDependencyCycle is returned when a new dependency would make tasks wait on each other
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+	// ErrStatusNotFound is returned for a workflow status that does not exist or belongs to another user
+	ErrStatusNotFound = errors.New("status not found")
+
+	// ErrDependencyNotFound is returned when removing a dependency that does not exist
+	ErrDependencyNotFound = errors.New("dependency not found")
+
+	// ErrPreconditionFailed is returned when If-Match does not match the task's current version
+	ErrPreconditionFailed = errors.New("task was modified by another request")
+
+	// ErrRevisionNotFound is returned when reverting a task to a revision it does not have
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	// ErrCommentNotFound is returned for a comment that does not exist on the task
+	ErrCommentNotFound = errors.New("comment not found")
+
+	// ErrCommentForbidden is returned when changing a comment written by another user
+	ErrCommentForbidden = errors.New("comment belongs to another user")
+
+	// ErrChecklistItemNotFound is returned for a checklist item that does not exist on the task
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+
+	// ErrAttachmentNotFound is returned for an attachment that does not exist on the task
+	ErrAttachmentNotFound = errors.New("attachment not found")
+
+	// ErrAttachmentTooLarge is returned for an upload over the per-file size limit
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+
+	// ErrQuotaExceeded is returned when an upload would take the user over their storage quota
+	ErrQuotaExceeded = errors.New("attachment storage quota exceeded")
+
+	// ErrUnsupportedMediaType is returned for an upload whose content is not an accepted file type
+	ErrUnsupportedMediaType = errors.New("unsupported file type")
+
+	// ErrInvalidRefreshToken is returned for a refresh token that is unknown, expired or revoked
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+	// ErrRefreshTokenReused is returned when a refresh token is presented again after it was
+	// rotated, the whole login it belongs to is revoked
+	ErrRefreshTokenReused = errors.New("refresh token was already used, the session has been revoked")
+
+	// ErrInvalidResetToken is returned for a password reset token that is unknown, used or expired
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+	// ErrInvalidVerificationToken is returned for an email verification token that is unknown, used or expired
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+
+	// ErrEmailNotVerified is returned when an unverified account does something that needs a verified email
+	ErrEmailNotVerified = errors.New("email address is not verified")
+
+	// ErrTooManyRequests is returned when an action is repeated before its cooldown has passed
+	ErrTooManyRequests = errors.New("too many requests, try again later")
+
+	// ErrIncorrectPassword is returned for a wrong password, at login also for an unknown email
+	ErrIncorrectPassword = errors.New("password incorrect")
+
+	// ErrTwoFactorEnabled is returned when enrolling in two-factor authentication while it is on
+	ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+
+	// ErrTwoFactorNotEnabled is returned when turning off two-factor authentication that is not on
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+
+	// ErrInvalidTwoFactorCode is returned for a TOTP or recovery code that is wrong or was used already
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+
+	// ErrInvalidLoginChallenge is returned for a two-factor login challenge that is unknown or expired
+	ErrInvalidLoginChallenge = errors.New("invalid or expired login challenge")
+
+	// ErrSessionNotFound is returned for a session that does not exist or belongs to another user
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrTransitionNotAllowed is returned when the workflow does not allow moving a task between two statuses
+	ErrTransitionNotAllowed = errors.New("status transition not allowed")
+)
+
+// ErrBulkAborted is reported for the operations of an atomic bulk request that were not applied
+// because another operation failed
+var ErrBulkAborted = errors.New("not applied, another operation of the atomic request failed")
+
+// TransitionError is an ErrTransitionNotAllowed that names the statuses the task may move to instead
+type TransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s, allowed next statuses: %s",
+		ErrTransitionNotAllowed, e.From, e.To, strings.Join(e.Allowed, ", "))
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrTransitionNotAllowed
+}
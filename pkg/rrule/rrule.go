@@ -0,0 +1,251 @@
+// Package rrule implements the subset of iCalendar (RFC 5545) recurrence rules used for
+// repeating tasks: FREQ, INTERVAL, BYDAY (without ordinals), COUNT and UNTIL.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies supported in the FREQ part of a rule
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// maxIterations bounds the search for the next occurrence so a rule can never loop forever
+const maxIterations = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int        // total number of occurrences, 0 means unlimited
+	Until    *time.Time // last allowed occurrence, inclusive
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" is accepted and ignored.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("empty recurrence rule")
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq := strings.ToUpper(value)
+			switch freq {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = freq
+			default:
+				return nil, fmt.Errorf("unsupported recurrence frequency %q", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid recurrence interval %q", value)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported recurrence weekday %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid recurrence count %q", value)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("recurrence rule is missing FREQ")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("recurrence rule cannot have both COUNT and UNTIL")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Daily && rule.Freq != Weekly {
+		return nil, errors.New("BYDAY is only supported for DAILY and WEEKLY rules")
+	}
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// a date-only UNTIL includes the whole day
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid recurrence until %q", value)
+}
+
+// String formats the rule back into its RRULE representation
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, weekdayNames[day])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence that follows current, which must itself be an occurrence of
+// the rule. occurrence is the 1-based position of current in the series and is checked
+// against COUNT. ok is false once the series is exhausted.
+func (r *Rule) Next(current time.Time, occurrence int) (next time.Time, ok bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	switch r.Freq {
+	case Daily:
+		next, ok = r.nextDaily(current)
+		if !ok {
+			return time.Time{}, false
+		}
+	case Weekly:
+		next = r.nextWeekly(current)
+	case Monthly:
+		next, ok = addMonths(current, r.Interval)
+		if !ok {
+			return time.Time{}, false
+		}
+	case Yearly:
+		next, ok = addMonths(current, 12*r.Interval)
+		if !ok {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// nextDaily steps INTERVAL days at a time until it lands on a BYDAY day. The weekdays repeat
+// after at most seven steps, so a rule like INTERVAL=7;BYDAY=TU started on a Wednesday never
+// lands on one and has no next occurrence.
+func (r *Rule) nextDaily(current time.Time) (time.Time, bool) {
+	next := current.AddDate(0, 0, r.Interval)
+	if len(r.ByDay) == 0 {
+		return next, true
+	}
+	for i := 0; i < 7; i++ {
+		if r.onDay(next) {
+			return next, true
+		}
+		next = next.AddDate(0, 0, r.Interval)
+	}
+	return time.Time{}, false
+}
+
+// nextWeekly walks the remaining BYDAY days of the current week, then jumps INTERVAL weeks
+// ahead. Weeks start on Monday, the RFC 5545 default WKST.
+func (r *Rule) nextWeekly(current time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return current.AddDate(0, 0, 7*r.Interval)
+	}
+
+	sinceMonday := (int(current.Weekday()) + 6) % 7
+	for i := 1; sinceMonday+i < 7; i++ {
+		if day := current.AddDate(0, 0, i); r.onDay(day) {
+			return day
+		}
+	}
+
+	weekStart := current.AddDate(0, 0, 7*r.Interval-sinceMonday)
+	for i := 0; ; i++ {
+		if day := weekStart.AddDate(0, 0, i); r.onDay(day) {
+			return day
+		}
+	}
+}
+
+func (r *Rule) onDay(t time.Time) bool {
+	for _, day := range r.ByDay {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// addMonths moves forward in steps of months, skipping months that do not have the
+// day of current (e.g. the 31st), as RFC 5545 requires instead of rolling over.
+func addMonths(current time.Time, months int) (time.Time, bool) {
+	year, month, day := current.Date()
+	hour, min, sec := current.Clock()
+	for i := 1; i <= maxIterations; i++ {
+		next := time.Date(year, month+time.Month(i*months), day, hour, min, sec, current.Nanosecond(), current.Location())
+		if next.Day() == day {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
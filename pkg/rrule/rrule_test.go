@@ -0,0 +1,137 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;interval=2;byday=mo,we;count=10", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10"},
+		{"FREQ=DAILY;INTERVAL=1;UNTIL=20240131T120000Z", "FREQ=DAILY;UNTIL=20240131T120000Z"},
+		{"FREQ=MONTHLY;UNTIL=20240131", "FREQ=MONTHLY;UNTIL=20240131T235959Z"},
+		{"FREQ=YEARLY;INTERVAL=4", "FREQ=YEARLY;INTERVAL=4"},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"RRULE:",
+		"FREQ",
+		"FREQ=HOURLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=DAILY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=DAILY;BYSETPOS=1",
+	} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+
+	// 2024-01-01 is a Monday
+	tests := []struct {
+		name       string
+		rule       string
+		current    time.Time
+		occurrence int
+		want       time.Time
+		ok         bool
+	}{
+		{"daily", "FREQ=DAILY", at(2024, 1, 1), 1, at(2024, 1, 2), true},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", at(2024, 1, 1), 1, at(2024, 1, 4), true},
+		{"daily across month end", "FREQ=DAILY", at(2024, 1, 31), 1, at(2024, 2, 1), true},
+		{"daily byday same week", "FREQ=DAILY;BYDAY=MO,WE,FR", at(2024, 1, 1), 1, at(2024, 1, 3), true},
+		{"daily byday next week", "FREQ=DAILY;BYDAY=MO,WE,FR", at(2024, 1, 5), 1, at(2024, 1, 8), true},
+		{"daily interval byday", "FREQ=DAILY;INTERVAL=2;BYDAY=MO", at(2024, 1, 1), 1, at(2024, 1, 15), true},
+		{"daily byday never reached", "FREQ=DAILY;INTERVAL=7;BYDAY=TU", at(2024, 1, 3), 1, time.Time{}, false},
+		{"weekly", "FREQ=WEEKLY", at(2024, 1, 1), 1, at(2024, 1, 8), true},
+		{"weekly interval", "FREQ=WEEKLY;INTERVAL=2", at(2024, 1, 1), 1, at(2024, 1, 15), true},
+		{"weekly byday same week", "FREQ=WEEKLY;BYDAY=TU,TH", at(2024, 1, 2), 1, at(2024, 1, 4), true},
+		{"weekly byday next week", "FREQ=WEEKLY;BYDAY=TU,TH", at(2024, 1, 4), 1, at(2024, 1, 9), true},
+		{"weekly interval byday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", at(2024, 1, 5), 1, at(2024, 1, 15), true},
+		{"weekly byday sunday ends week", "FREQ=WEEKLY;BYDAY=MO,SU", at(2024, 1, 7), 1, at(2024, 1, 8), true},
+		{"monthly", "FREQ=MONTHLY", at(2024, 1, 15), 1, at(2024, 2, 15), true},
+		{"monthly interval", "FREQ=MONTHLY;INTERVAL=3", at(2024, 11, 15), 1, at(2025, 2, 15), true},
+		{"monthly skips short months", "FREQ=MONTHLY", at(2024, 1, 31), 1, at(2024, 3, 31), true},
+		{"monthly 30th skips february", "FREQ=MONTHLY", at(2024, 1, 30), 1, at(2024, 3, 30), true},
+		{"monthly 29th in leap year", "FREQ=MONTHLY", at(2024, 1, 29), 1, at(2024, 2, 29), true},
+		{"yearly leap day", "FREQ=YEARLY", at(2024, 2, 29), 1, at(2028, 2, 29), true},
+		{"count not reached", "FREQ=DAILY;COUNT=3", at(2024, 1, 2), 2, at(2024, 1, 3), true},
+		{"count reached", "FREQ=DAILY;COUNT=3", at(2024, 1, 3), 3, time.Time{}, false},
+		{"until inclusive", "FREQ=DAILY;UNTIL=20240103", at(2024, 1, 2), 1, at(2024, 1, 3), true},
+		{"until passed", "FREQ=DAILY;UNTIL=20240103", at(2024, 1, 3), 1, time.Time{}, false},
+		{"until with time", "FREQ=WEEKLY;UNTIL=20240108T085959Z", at(2024, 1, 1), 1, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+			}
+			got, ok := rule.Next(tt.current, tt.occurrence)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, %v, want %s, %v", tt.current, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNextKeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available:", err)
+	}
+
+	tests := []struct {
+		rule    string
+		current time.Time
+		want    time.Time
+	}{
+		// clocks spring forward on 2024-03-10 and fall back on 2024-11-03
+		{"FREQ=DAILY", time.Date(2024, 3, 9, 9, 0, 0, 0, loc), time.Date(2024, 3, 10, 9, 0, 0, 0, loc)},
+		{"FREQ=DAILY", time.Date(2024, 11, 2, 9, 0, 0, 0, loc), time.Date(2024, 11, 3, 9, 0, 0, 0, loc)},
+		{"FREQ=WEEKLY;BYDAY=SA,SU", time.Date(2024, 3, 9, 9, 0, 0, 0, loc), time.Date(2024, 3, 10, 9, 0, 0, 0, loc)},
+		{"FREQ=MONTHLY", time.Date(2024, 2, 20, 9, 0, 0, 0, loc), time.Date(2024, 3, 20, 9, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+		}
+		got, ok := rule.Next(tt.current, 1)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("%s Next(%s) = %s, %v, want %s", tt.rule, tt.current, got, ok, tt.want)
+		}
+		if got.Hour() != 9 {
+			t.Errorf("%s Next(%s) hour = %d, want 9", tt.rule, tt.current, got.Hour())
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfcVectors are the SHA-1 test vectors of RFC 6238 appendix B, cut to the last six digits
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCode(t *testing.T) {
+	for _, tt := range rfcVectors {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d error: %v", tt.unix, err)
+		}
+		if got != tt.code {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestCodeAcceptsLowercaseAndPaddedSecrets(t *testing.T) {
+	for _, secret := range []string{strings.ToLower(rfcSecret), rfcSecret + "===="} {
+		got, err := Code(secret, 1)
+		if err != nil {
+			t.Fatalf("Code(%q) error: %v", secret, err)
+		}
+		if got != "287082" {
+			t.Errorf("Code(%q) = %s, want 287082", secret, got)
+		}
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code with an invalid secret succeeded, want error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+	previous, _ := Code(rfcSecret, step-1)
+	next, _ := Code(rfcSecret, step+1)
+	tooOld, _ := Code(rfcSecret, step-2)
+
+	tests := []struct {
+		name     string
+		code     string
+		skew     int
+		wantStep int64
+		ok       bool
+	}{
+		{"current step", "050471", 1, step, true},
+		{"spaces are ignored", " 050 471 ", 1, step, true},
+		{"previous step within skew", previous, 1, step - 1, true},
+		{"next step within skew", next, 1, step + 1, true},
+		{"outside skew", tooOld, 1, 0, false},
+		{"previous step without skew", previous, 0, 0, false},
+		{"wrong code", "123456", 1, 0, false},
+		{"too short", "05047", 1, 0, false},
+		{"too long", "0504711", 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := Validate(rfcSecret, tt.code, now, tt.skew)
+			if ok != tt.ok || gotStep != tt.wantStep {
+				t.Errorf("Validate(%q) = %d, %v, want %d, %v", tt.code, gotStep, ok, tt.wantStep, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret error: %v", err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("GenerateSecret returned invalid base32 %q: %v", secret, err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("secret has %d bytes, want %d", len(key), secretSize)
+	}
+
+	other, _ := GenerateSecret()
+	if other == secret {
+		t.Error("GenerateSecret returned the same secret twice")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Todo App", "user@example.com", rfcSecret)
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("URI %q does not parse: %v", uri, err)
+	}
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" {
+		t.Errorf("URI %q is not an otpauth://totp URI", uri)
+	}
+	if parsed.Path != "/Todo App:user@example.com" {
+		t.Errorf("URI label = %q, want %q", parsed.Path, "/Todo App:user@example.com")
+	}
+
+	query := parsed.Query()
+	want := map[string]string{"secret": rfcSecret, "issuer": "Todo App", "algorithm": "SHA1", "digits": "6", "period": "30"}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("URI %s = %q, want %q", key, got, value)
+		}
+	}
+}